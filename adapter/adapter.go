@@ -0,0 +1,59 @@
+/*
+Package adapter deploys the same function logic on every runtime uCode functions run on.
+
+A Handler holds the business logic, the adapters turn it into
+
+	HTTP(h)     a plain http.Handler for standalone containers
+	OpenFaaS(h) a Handle(req handler.Request) function of the OpenFaaS golang-http template,
+	            see testdata/openfaas/function/handler.go
+	CLI(h)      an entry point reading the request from stdin and writing the response to stdout
+
+Every adapter decodes the request the same way, answers with an ucodesdk.Response
+of status "done", and turns a Handler error into a Response of status "error".
+*/
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+)
+
+// Handler is the business logic of a function.
+type Handler func(ctx context.Context, request ucodesdk.FunctionRequest) (ucodesdk.Response, error)
+
+// invoke runs the handler with a raw request body and returns the status code and body of the answer.
+func invoke(ctx context.Context, handler Handler, body []byte) (int, []byte) {
+	var request ucodesdk.FunctionRequest
+
+	if err := json.Unmarshal(body, &request); err != nil {
+		return errorResponse(http.StatusBadRequest, "Error on unmarshal request", err)
+	}
+
+	response, err := handler(ctx, request)
+	if err != nil {
+		return errorResponse(http.StatusInternalServerError, "Error on handling request", err)
+	}
+
+	if response.Status == "" {
+		response.Status = "done"
+	}
+
+	responseByte, err := json.Marshal(response)
+	if err != nil {
+		return errorResponse(http.StatusInternalServerError, "Error marshalling response", err)
+	}
+
+	return http.StatusOK, responseByte
+}
+
+func errorResponse(statusCode int, message string, err error) (int, []byte) {
+	responseByte, _ := json.Marshal(ucodesdk.Response{
+		Status: "error",
+		Data:   map[string]interface{}{"message": message, "error": err.Error()},
+	})
+
+	return statusCode, responseByte
+}
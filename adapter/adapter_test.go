@@ -0,0 +1,98 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+var double Handler = func(ctx context.Context, request ucodesdk.FunctionRequest) (ucodesdk.Response, error) {
+	if request.Data.ObjectData["n"] == nil {
+		return ucodesdk.Response{}, errors.New("n is required")
+	}
+
+	n, _ := request.Data.ObjectData["n"].(float64)
+	return ucodesdk.Response{Data: map[string]interface{}{
+		"result":         n * 2,
+		"correlation_id": ucodesdk.CorrelationId(ctx),
+	}}, nil
+}
+
+func TestAdapters(t *testing.T) {
+	var (
+		body     = `{"data": {"object_data": {"n": 21}}}`
+		response ucodesdk.Response
+	)
+
+	t.Run("HTTP", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		request.Header.Set(ucodesdk.CorrelationIdHeader, "correlation-1")
+		recorder := httptest.NewRecorder()
+
+		HTTP(double).ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Equal(t, "done", response.Status)
+		assert.Equal(t, float64(42), response.Data["result"])
+		assert.Equal(t, "correlation-1", response.Data["correlation_id"])
+
+		recorder = httptest.NewRecorder()
+		HTTP(double).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{")))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("OpenFaaS", func(t *testing.T) {
+		resp, err := OpenFaaS(double)(context.Background(), Request{Body: []byte(`{"data": {}}`), Header: http.Header{}})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.NoError(t, json.Unmarshal(resp.Body, &response))
+		assert.Equal(t, "error", response.Status)
+		assert.Equal(t, "n is required", response.Data["error"])
+	})
+
+	t.Run("CLI", func(t *testing.T) {
+		var out bytes.Buffer
+
+		statusCode, err := RunCLI(context.Background(), double, strings.NewReader(body), &out)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &response))
+		assert.Equal(t, float64(42), response.Data["result"])
+	})
+}
+
+// TestOpenFaaSTemplate runs the test of the example handler file, built against the handler package of the golang-http template.
+func TestOpenFaaSTemplate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the example function")
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+
+	cmd := exec.Command(goBin, "test", ".")
+	cmd.Dir = "testdata/openfaas/function"
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+
+	out, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
+
+func TestOpenFaaSWithoutContext(t *testing.T) {
+	resp, err := OpenFaaS(double)(nil, Request{Body: []byte(`{"data": {"object_data": {"n": 1}}}`), Header: http.Header{}})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
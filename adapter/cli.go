@@ -0,0 +1,47 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+/*
+CLI reads the request from stdin, writes the response to stdout and exits.
+The exit code is 1 when the handler fails and 2 when the request can not be read.
+
+	func main() {
+		adapter.CLI(logic)
+	}
+
+	echo '{"data": {"object_data": {}}}' | ./function
+*/
+func CLI(handler Handler) {
+	statusCode, err := RunCLI(context.Background(), handler, os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if statusCode != http.StatusOK {
+		os.Exit(1)
+	}
+}
+
+// RunCLI runs the handler with the request read from in, writes the response to out and returns its status code.
+func RunCLI(ctx context.Context, handler Handler, in io.Reader, out io.Writer) (int, error) {
+	body, err := io.ReadAll(in)
+	if err != nil {
+		return 0, err
+	}
+
+	statusCode, responseByte := invoke(ctx, handler, body)
+
+	if _, err = fmt.Fprintf(out, "%s\n", responseByte); err != nil {
+		return statusCode, err
+	}
+
+	return statusCode, nil
+}
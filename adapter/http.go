@@ -0,0 +1,29 @@
+package adapter
+
+import (
+	"io"
+	"net/http"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+)
+
+// HTTP wraps the handler as a plain http.Handler. The correlation id of the request is passed on through the context.
+func HTTP(handler Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			statusCode, responseByte := errorResponse(http.StatusBadRequest, "Error on getting request body", err)
+			writeResponse(w, statusCode, responseByte)
+			return
+		}
+
+		statusCode, responseByte := invoke(ucodesdk.CorrelationContext(r), handler, body)
+		writeResponse(w, statusCode, responseByte)
+	})
+}
+
+func writeResponse(w http.ResponseWriter, statusCode int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
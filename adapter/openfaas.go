@@ -0,0 +1,52 @@
+package adapter
+
+import (
+	"context"
+	"net/http"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+)
+
+type (
+	// Request mirrors handler.Request of github.com/openfaas/templates-sdk/go-http.
+	Request struct {
+		Body        []byte
+		Header      http.Header
+		QueryString string
+		Method      string
+		Host        string
+	}
+
+	// Response mirrors handler.Response of github.com/openfaas/templates-sdk/go-http.
+	Response struct {
+		Body       []byte
+		StatusCode int
+		Header     http.Header
+	}
+)
+
+/*
+OpenFaaS wraps the handler as the Handle function of the OpenFaaS golang-http template.
+The handler file of such a function, with the Handle(req handler.Request) (handler.Response, error)
+the template calls, is testdata/openfaas/function/handler.go: copy it and set Logic.
+
+Handler errors are reported in the response, the returned error is always nil.
+*/
+func OpenFaaS(handler Handler) func(ctx context.Context, req Request) (Response, error) {
+	return func(ctx context.Context, req Request) (Response, error) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if id := req.Header.Get(ucodesdk.CorrelationIdHeader); id != "" {
+			ctx = ucodesdk.WithCorrelationId(ctx, id)
+		}
+
+		statusCode, body := invoke(ctx, handler, req.Body)
+
+		return Response{
+			Body:       body,
+			StatusCode: statusCode,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+		}, nil
+	}
+}
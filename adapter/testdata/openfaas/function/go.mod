@@ -0,0 +1,15 @@
+module example.com/function
+
+go 1.22
+
+require (
+	github.com/golanguzb70/ucode-sdk v0.0.0
+	github.com/openfaas/templates-sdk/go-http v0.0.0
+)
+
+require github.com/spf13/cast v1.7.0 // indirect
+
+replace (
+	github.com/golanguzb70/ucode-sdk => ../../../..
+	github.com/openfaas/templates-sdk/go-http => ../go-http
+)
@@ -0,0 +1,31 @@
+/*
+Package function is the handler file of an OpenFaaS golang-http function running
+an adapter.Handler. Copy it next to the logic of the function and set Logic.
+*/
+package function
+
+import (
+	"context"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/adapter"
+	handler "github.com/openfaas/templates-sdk/go-http"
+)
+
+// Logic is the business logic of the function.
+var Logic adapter.Handler = func(ctx context.Context, request ucodesdk.FunctionRequest) (ucodesdk.Response, error) {
+	return ucodesdk.Response{Data: map[string]interface{}{"table_slug": request.Data.TableSlug}}, nil
+}
+
+// Handle is called by the OpenFaaS golang-http template.
+func Handle(req handler.Request) (handler.Response, error) {
+	resp, err := adapter.OpenFaaS(Logic)(req.Context(), adapter.Request{
+		Body:        req.Body,
+		Header:      req.Header,
+		QueryString: req.QueryString,
+		Method:      req.Method,
+		Host:        req.Host,
+	})
+
+	return handler.Response(resp), err
+}
@@ -0,0 +1,38 @@
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	handler "github.com/openfaas/templates-sdk/go-http"
+)
+
+// TestHandle calls Handle the way the main.go of the golang-http template does.
+func TestHandle(t *testing.T) {
+	req := handler.Request{
+		Body:   []byte(`{"data": {"table_slug": "houses"}}`),
+		Header: http.Header{"Content-Type": []string{"application/json"}},
+		Method: http.MethodPost,
+		Host:   "gateway:8080",
+	}
+	req.WithContext(context.Background())
+
+	resp, err := Handle(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status code %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	var response ucodesdk.Response
+	if err = json.Unmarshal(resp.Body, &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != "done" || response.Data["table_slug"] != "houses" {
+		t.Fatalf("unexpected response %s", resp.Body)
+	}
+}
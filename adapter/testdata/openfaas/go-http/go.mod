@@ -0,0 +1,3 @@
+module github.com/openfaas/templates-sdk/go-http
+
+go 1.22
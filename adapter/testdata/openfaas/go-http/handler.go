@@ -0,0 +1,35 @@
+// Package handler has the request and response types of github.com/openfaas/templates-sdk/go-http,
+// the package the OpenFaaS golang-http template calls Handle with.
+package handler
+
+import (
+	"context"
+	"net/http"
+)
+
+// Response of function call
+type Response struct {
+	Body       []byte
+	StatusCode int
+	Header     http.Header
+}
+
+// Request of function call
+type Request struct {
+	Body        []byte
+	Header      http.Header
+	QueryString string
+	Method      string
+	Host        string
+	ctx         context.Context
+}
+
+// Context is set by the template to the context of the incoming http request.
+func (r *Request) Context() context.Context {
+	return r.ctx
+}
+
+// WithContext sets the context of the request.
+func (r *Request) WithContext(ctx context.Context) {
+	r.ctx = ctx
+}
@@ -0,0 +1,154 @@
+package ucodesdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrUnsupportedOnBackend is returned, before anything is sent, by methods the database of the app can't run.
+var ErrUnsupportedOnBackend = errors.New("not supported on the backend of the app")
+
+const (
+	// probeTable is the table the backend probe is sent to, every app has it.
+	probeTable = "role"
+
+	defaultBackendRetry = time.Minute
+)
+
+// detectedBackends caches the probes of all clients, keyed by base url and app id.
+var detectedBackends sync.Map
+
+type (
+	// Backend is the database of an app, it decides how some methods are sent. It is empty when unknown.
+	Backend string
+
+	// Capabilities tells which methods the database of an app supports.
+	Capabilities struct {
+		// Backend is empty when the database is unknown, the methods are then sent like they are to Mongo apps.
+		Backend Backend
+		// Aggregation is GetListAggregation.
+		Aggregation bool
+		// ManyToMany is the many-to-many endpoint, without it AppendManyToMany, DeleteManyToMany and Relations update the <table_to>_ids field.
+		ManyToMany bool
+	}
+
+	// probeResult is a cached probe, an unknown database or a failed probe expires.
+	probeResult struct {
+		backend Backend
+		err     error
+		expires time.Time
+	}
+)
+
+const (
+	BackendMongo    Backend = "mongo"
+	BackendPostgres Backend = "postgres"
+)
+
+// capabilities returns the capabilities of the database, an unknown one keeps everything.
+func (b Backend) capabilities() Capabilities {
+	return Capabilities{
+		Backend:     b,
+		Aggregation: b != BackendPostgres,
+		ManyToMany:  b != BackendPostgres,
+	}
+}
+
+func (o *object) Capabilities(appId string) (Capabilities, error) {
+	backend, err := o.detect(context.Background(), appId)
+	return backend.capabilities(), err
+}
+
+// supports returns ErrUnsupportedOnBackend when the database of the app is known and can't run the method.
+func (o *object) supports(ctx context.Context, appId, method string, supported func(Capabilities) bool) error {
+	backend := o.backend(ctx, appId)
+	if !supported(backend.capabilities()) {
+		return fmt.Errorf("%s on %s: %w", method, backend, ErrUnsupportedOnBackend)
+	}
+
+	return nil
+}
+
+// backend returns the database of the app, empty when it is unknown because the probe failed.
+func (o *object) backend(ctx context.Context, appId string) Backend {
+	backend, _ := o.detect(ctx, appId)
+	return backend
+}
+
+/*
+detect returns the database of the app from Config.Backend and Config.Backends.
+Only when it is not configured, a probe is sent and its result cached for all clients
+of the same base url: Mongo for good, an unknown database or a failed probe for
+Config.BackendRetry, so the methods needing it do not probe again on every call.
+*/
+func (o *object) detect(ctx context.Context, appId string) (Backend, error) {
+	if appId == "" {
+		appId = o.config.AppId
+	}
+
+	if backend := o.config.backend(appId); backend != "" {
+		return backend, nil
+	}
+
+	key := o.config.BaseURL + "\x00" + appId
+	if cached, ok := detectedBackends.Load(key); ok {
+		result := cached.(probeResult)
+		if result.expires.IsZero() || time.Now().Before(result.expires) {
+			return result.backend, result.err
+		}
+	}
+
+	result := probeResult{}
+	result.backend, result.err = o.probe(ctx, appId)
+	if result.err != nil {
+		result.err = fmt.Errorf("detecting the backend of app %s: %w", appId, result.err)
+	}
+	if result.backend == "" {
+		retry := o.config.BackendRetry
+		if retry <= 0 {
+			retry = defaultBackendRetry
+		}
+		result.expires = time.Now().Add(retry)
+	}
+
+	detectedBackends.Store(key, result)
+	return result.backend, result.err
+}
+
+/*
+probe sends an aggregation of one row of the role table. Mongo apps answer it, any
+other answer leaves the database unknown: only Config.Backend and Config.Backends
+tell a Postgres app, the probe does not guess it from an error.
+*/
+func (o *object) probe(ctx context.Context, appId string) (Backend, error) {
+	var (
+		url     = fmt.Sprintf("%s/v2/items/%s/aggregation", o.config.BaseURL, probeTable)
+		request = Request{Data: map[string]interface{}{"pipelines": []map[string]interface{}{{"$limit": 1}}}}
+	)
+
+	statusCode, respByte, err := o.send(ctx, url, http.MethodPost, request, o.headers(appId))
+	switch {
+	case err != nil:
+		return "", err
+	case statusCode < http.StatusBadRequest:
+		return BackendMongo, nil
+	case statusCode == http.StatusBadRequest:
+		// an app without aggregation, or without the role table
+		return "", nil
+	default:
+		return "", &HTTPError{StatusCode: statusCode, Body: respByte}
+	}
+}
+
+// backend returns the configured database of the app, empty when it is not configured.
+func (cfg *Config) backend(appId string) Backend {
+	if appId == cfg.AppId && cfg.Backend != "" {
+		return cfg.Backend
+	}
+
+	return cfg.Backends[appId]
+}
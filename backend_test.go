@@ -0,0 +1,157 @@
+package ucodesdk_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+// probes returns the number of backend probes the server got.
+func probes(requests []string) int {
+	count := 0
+	for _, request := range requests {
+		if strings.HasSuffix(request, "/v2/items/role/aggregation") {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCapabilities(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		mongoAppId    = "mongo_app_id"
+		postgresAppId = "postgres_app_id"
+		ctx           = context.Background()
+		postgres      = ucodesdk.New(&ucodesdk.Config{
+			AppId:    mongoAppId,
+			BaseURL:  server.URL,
+			Backends: map[string]ucodesdk.Backend{postgresAppId: ucodesdk.BackendPostgres},
+		})
+	)
+	server.UsePostgres(postgresAppId)
+
+	capabilities, err := server.Client(mongoAppId).Info().Capabilities(mongoAppId)
+	assert.NoError(t, err)
+	assert.Equal(t, ucodesdk.Capabilities{Backend: ucodesdk.BackendMongo, Aggregation: true, ManyToMany: true}, capabilities)
+
+	// a configured backend is not probed
+	capabilities, err = postgres.Info().Capabilities(postgresAppId)
+	assert.NoError(t, err)
+	assert.Equal(t, ucodesdk.Capabilities{Backend: ucodesdk.BackendPostgres}, capabilities)
+
+	sent := len(server.Requests())
+	_, _, err = postgres.GetListAggregation(&ucodesdk.Argument{AppId: postgresAppId, TableSlug: "houses", Request: ucodesdk.Request{Data: map[string]interface{}{}}})
+	assert.ErrorIs(t, err, ucodesdk.ErrUnsupportedOnBackend)
+	assert.Len(t, server.Requests(), sent)
+
+	houses := server.Seed(postgresAppId, "houses", map[string]interface{}{"name": "house"})
+	rooms := server.Seed(postgresAppId, "room", map[string]interface{}{"name": "room"})
+	configured := ucodesdk.New(&ucodesdk.Config{AppId: postgresAppId, BaseURL: server.URL, Backend: ucodesdk.BackendPostgres})
+	assert.NoError(t, configured.Relations().Add(ctx, "houses", "room", houses[0], rooms))
+
+	// the mongo backend is probed once for all clients
+	_, err = server.Client(mongoAppId).V2().GetListAggregation(ctx, "houses", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, probes(server.Requests()))
+}
+
+func TestCapabilitiesUnknown(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		ctx   = context.Background()
+		retry = 50 * time.Millisecond
+	)
+
+	t.Run("failed probe", func(t *testing.T) {
+		var (
+			appId    = "failing_app_id"
+			ucodeApi = ucodesdk.New(&ucodesdk.Config{BaseURL: server.URL, AppId: appId, BackendRetry: retry})
+			houses   = server.Seed(appId, "houses", map[string]interface{}{"name": "house"})
+			rooms    = server.Seed(appId, "room", map[string]interface{}{"name": "room"})
+		)
+		server.FailWhen(func(method, path string, body []byte) bool {
+			return strings.HasSuffix(path, "/role/aggregation")
+		})
+		defer server.FailWhen(nil)
+
+		// the methods are sent like they are to Mongo apps, the failed probe is kept
+		for i := 0; i < 3; i++ {
+			_, err := ucodeApi.AppendManyToMany(&ucodesdk.Argument{
+				TableSlug: "houses",
+				Request:   ucodesdk.Request{Data: map[string]interface{}{"table_from": "houses", "table_to": "room", "id_from": houses[0], "id_to": rooms}},
+			})
+			assert.NoError(t, err)
+		}
+		assert.Contains(t, server.Requests(), "PUT /v2/items/many-to-many")
+		assert.Equal(t, 1, probes(server.Requests()))
+
+		capabilities, err := ucodeApi.Info().Capabilities(appId)
+		assert.Error(t, err)
+		assert.Equal(t, ucodesdk.Capabilities{Aggregation: true, ManyToMany: true}, capabilities)
+		assert.Equal(t, 1, probes(server.Requests()))
+
+		// the probe is sent again once BackendRetry has passed
+		server.FailWhen(nil)
+		time.Sleep(retry)
+		capabilities, err = ucodeApi.Info().Capabilities(appId)
+		assert.NoError(t, err)
+		assert.Equal(t, ucodesdk.BackendMongo, capabilities.Backend)
+		assert.Equal(t, 2, probes(server.Requests()))
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		var (
+			appId          = "unknown_app_id"
+			badRequest     atomic.Bool
+			probesAnswered atomic.Int64
+		)
+		badRequest.Store(true)
+		server.Seed(appId, "houses", map[string]interface{}{"name": "house"})
+
+		// a 400 to the probe, which does not make the app a Postgres one
+		unknown := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/role/aggregation") {
+				probesAnswered.Add(1)
+				if badRequest.Load() {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"status": "BAD_REQUEST", "data": "aggregation is not supported on postgres"}`))
+					return
+				}
+			}
+			server.ServeHTTP(w, r)
+		}))
+		defer unknown.Close()
+
+		ucodeApi := ucodesdk.New(&ucodesdk.Config{BaseURL: unknown.URL, AppId: appId, BackendRetry: retry})
+		for i := 0; i < 3; i++ {
+			_, err := ucodeApi.V2().GetListAggregation(ctx, "houses", nil)
+			assert.NoError(t, err)
+		}
+		assert.EqualValues(t, 1, probesAnswered.Load())
+
+		capabilities, err := ucodeApi.Info().Capabilities(appId)
+		assert.NoError(t, err)
+		assert.Equal(t, ucodesdk.Capabilities{Aggregation: true, ManyToMany: true}, capabilities)
+		assert.EqualValues(t, 1, probesAnswered.Load())
+
+		badRequest.Store(false)
+		time.Sleep(retry)
+		capabilities, err = ucodeApi.Info().Capabilities(appId)
+		assert.NoError(t, err)
+		assert.Equal(t, ucodesdk.BackendMongo, capabilities.Backend)
+		assert.EqualValues(t, 2, probesAnswered.Load())
+	})
+}
@@ -0,0 +1,88 @@
+/*
+Package backup saves the tables of a uCode app to an archive and restores
+them into the same or another app.
+
+An archive is a zip file:
+
+	manifest.json			format version, source app and counts
+	tables/<table_slug>.ndjson	one object per line
+	links/<table_from>.<table_to>.ndjson	many-to-many links, {"id_from": ..., "id_to": [...]} per line
+
+uCode has no endpoint listing the tables of an app, so the tables and
+many-to-many relations to save are given by the caller.
+*/
+package backup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Version is the archive format version written by Backup and read by Restore.
+const Version = 1
+
+const manifestName = "manifest.json"
+
+type (
+	Manifest struct {
+		Version   int       `json:"version"`
+		AppId     string    `json:"app_id"`
+		CreatedAt time.Time `json:"created_at"`
+		// Tables are in backup order, which is the order they are restored in.
+		Tables []TableInfo `json:"tables"`
+		Links  []LinkInfo  `json:"links"`
+	}
+
+	TableInfo struct {
+		TableSlug string `json:"table_slug"`
+		Count     int    `json:"count"`
+	}
+
+	LinkInfo struct {
+		Link
+		Count int `json:"count"`
+	}
+
+	/*
+		Link is a many-to-many relation. The linked ids are read from the
+		<table_to>_ids field of the table_from objects and restored with AppendManyToMany.
+	*/
+	Link struct {
+		TableFrom string `json:"table_from"`
+		TableTo   string `json:"table_to"`
+	}
+
+	// linkRecord is a line of a links file.
+	linkRecord struct {
+		IdFrom string   `json:"id_from"`
+		IdTo   []string `json:"id_to"`
+	}
+)
+
+// ParseLink parses a link given as table_from:table_to, e.g. houses:room.
+func ParseLink(s string) (Link, error) {
+	from, to, ok := strings.Cut(s, ":")
+	if !ok || from == "" || to == "" {
+		return Link{}, fmt.Errorf("invalid link %q, expected table_from:table_to", s)
+	}
+
+	return Link{TableFrom: from, TableTo: to}, nil
+}
+
+func (l Link) String() string {
+	return l.TableFrom + ":" + l.TableTo
+}
+
+// field is the field of the table_from objects holding the linked ids.
+func (l Link) field() string {
+	return l.TableTo + "_ids"
+}
+
+func tablePath(tableSlug string) string {
+	return "tables/" + tableSlug + ".ndjson"
+}
+
+func linkPath(link Link) string {
+	return "links/" + link.TableFrom + "." + link.TableTo + ".ndjson"
+}
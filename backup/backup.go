@@ -0,0 +1,134 @@
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/exporter"
+	"github.com/spf13/cast"
+)
+
+type BackupOptions struct {
+	// AppId is the app to back up, the app id of the client config by default.
+	AppId       string
+	DisableFaas bool
+	// Tables are saved in order, list referenced tables before the tables referencing them.
+	Tables []string
+	// Links are the many-to-many relations to save, their tables must be in Tables.
+	Links []Link
+	// PageSize is the limit of one GetListSlim call, 500 by default.
+	PageSize int
+}
+
+// Backup writes the tables and links of the app to w as a zip archive and returns its manifest.
+func Backup(ctx context.Context, ucodeApi ucodesdk.UcodeApis, w io.Writer, opts BackupOptions) (Manifest, error) {
+	manifest := Manifest{
+		Version:   Version,
+		AppId:     opts.AppId,
+		CreatedAt: time.Now().UTC(),
+	}
+	if manifest.AppId == "" {
+		manifest.AppId = ucodeApi.Config().AppId
+	}
+
+	if len(opts.Tables) == 0 {
+		return manifest, errors.New("no tables to back up")
+	}
+
+	tables := map[string]bool{}
+	for _, tableSlug := range opts.Tables {
+		if tables[tableSlug] {
+			return manifest, fmt.Errorf("table %s is listed twice", tableSlug)
+		}
+		tables[tableSlug] = true
+	}
+
+	// links are collected while their table_from is read
+	links := map[string][]Link{}
+	for _, link := range opts.Links {
+		if !tables[link.TableFrom] || !tables[link.TableTo] {
+			return manifest, fmt.Errorf("link %s: both tables must be backed up", link)
+		}
+		links[link.TableFrom] = append(links[link.TableFrom], link)
+	}
+
+	var (
+		archive = zip.NewWriter(w)
+		records = map[Link][]linkRecord{}
+	)
+
+	for _, tableSlug := range opts.Tables {
+		file, err := archive.Create(tablePath(tableSlug))
+		if err != nil {
+			return manifest, err
+		}
+
+		var (
+			encoder = json.NewEncoder(file)
+			count   int
+		)
+
+		err = exporter.Walk(ctx, ucodeApi, exporter.Options{
+			AppId:       opts.AppId,
+			TableSlug:   tableSlug,
+			DisableFaas: opts.DisableFaas,
+			PageSize:    opts.PageSize,
+		}, func(page []map[string]interface{}) error {
+			for _, object := range page {
+				for _, link := range links[tableSlug] {
+					ids := cast.ToStringSlice(object[link.field()])
+					if len(ids) > 0 {
+						records[link] = append(records[link], linkRecord{IdFrom: cast.ToString(object["guid"]), IdTo: ids})
+					}
+				}
+
+				if err := encoder.Encode(object); err != nil {
+					return err
+				}
+				count++
+			}
+			return nil
+		})
+		if err != nil {
+			return manifest, err
+		}
+
+		manifest.Tables = append(manifest.Tables, TableInfo{TableSlug: tableSlug, Count: count})
+	}
+
+	for _, link := range opts.Links {
+		file, err := archive.Create(linkPath(link))
+		if err != nil {
+			return manifest, err
+		}
+
+		encoder := json.NewEncoder(file)
+		for _, record := range records[link] {
+			if err = encoder.Encode(record); err != nil {
+				return manifest, err
+			}
+		}
+
+		manifest.Links = append(manifest.Links, LinkInfo{Link: link, Count: len(records[link])})
+	}
+
+	// the manifest is written last, an archive without it is incomplete
+	file, err := archive.Create(manifestName)
+	if err != nil {
+		return manifest, err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "    ")
+	if err = encoder.Encode(manifest); err != nil {
+		return manifest, err
+	}
+
+	return manifest, archive.Close()
+}
@@ -0,0 +1,186 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/spf13/cast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupRestore(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		source   = "source_app_id"
+		ucodeApi = server.Client(source)
+		ctx      = context.Background()
+		archive  bytes.Buffer
+	)
+	for i := 0; i < 5; i++ {
+		server.Seed(source, "room", map[string]interface{}{"guid": fmt.Sprintf("room-%d", i), "name": fmt.Sprintf("room %d", i)})
+	}
+	for i := 0; i < 5; i++ {
+		server.Seed(source, "houses", map[string]interface{}{
+			"guid":     fmt.Sprintf("house-%d", i),
+			"name":     fmt.Sprintf("house %d", i),
+			"price":    15000 + i,
+			"room_id":  fmt.Sprintf("room-%d", i),
+			"room_ids": []string{fmt.Sprintf("room-%d", i), fmt.Sprintf("room-%d", (i+1)%5)},
+		})
+	}
+
+	manifest, err := Backup(ctx, ucodeApi, &archive, BackupOptions{
+		Tables:   []string{"room", "houses"},
+		Links:    []Link{{TableFrom: "houses", TableTo: "room"}},
+		PageSize: 2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, source, manifest.AppId)
+	assert.Equal(t, []TableInfo{{TableSlug: "room", Count: 5}, {TableSlug: "houses", Count: 5}}, manifest.Tables)
+	assert.Equal(t, 5, manifest.Links[0].Count)
+
+	restoreWith := func(t *testing.T, ucodeApi ucodesdk.UcodeApis, appId string, opts RestoreOptions) RestoreReport {
+		opts.AppId = appId
+		opts.BatchSize = 2
+
+		report, err := Restore(ctx, ucodeApi, bytes.NewReader(archive.Bytes()), int64(archive.Len()), opts)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"room": 5, "houses": 5}, report.Tables)
+		assert.Equal(t, 5, report.Links)
+
+		return report
+	}
+	restoreTo := func(t *testing.T, appId string, opts RestoreOptions) RestoreReport {
+		return restoreWith(t, ucodeApi, appId, opts)
+	}
+
+	t.Run("same guids", func(t *testing.T) {
+		restoreTo(t, "copy_app_id", RestoreOptions{})
+
+		houses := server.Objects("copy_app_id", "houses")
+		assert.Len(t, houses, 5)
+		assert.Equal(t, "house-3", houses[3]["guid"])
+		assert.Equal(t, "room-3", houses[3]["room_id"])
+		assert.Equal(t, []string{"room-3", "room-4"}, cast.ToStringSlice(houses[3]["room_ids"]))
+		assert.EqualValues(t, 15003, cast.ToInt(houses[3]["price"]))
+	})
+
+	t.Run("new guids", func(t *testing.T) {
+		report := restoreTo(t, "clone_app_id", RestoreOptions{NewGuids: true})
+		assert.Len(t, report.Guids, 10)
+
+		houses := server.Objects("clone_app_id", "houses")
+		assert.Len(t, houses, 5)
+		assert.Equal(t, report.Guids["house-3"], houses[3]["guid"])
+		assert.Equal(t, report.Guids["room-3"], houses[3]["room_id"])
+		assert.Equal(t, []string{report.Guids["room-3"], report.Guids["room-4"]}, cast.ToStringSlice(houses[3]["room_ids"]))
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		var (
+			appId      = "resume_app_id"
+			checkpoint = filepath.Join(t.TempDir(), "restore.checkpoint")
+			requests   int
+		)
+
+		// the third houses batch fails once
+		server.FailWhen(func(method, path string, body []byte) bool {
+			if method != http.MethodPut || !strings.Contains(path, "multiple-update/houses") {
+				return false
+			}
+			requests++
+			return requests == 3
+		})
+		_, err := Restore(ctx, ucodeApi, bytes.NewReader(archive.Bytes()), int64(archive.Len()), RestoreOptions{AppId: appId, BatchSize: 2, NewGuids: true, Checkpoint: checkpoint})
+		assert.Error(t, err)
+		assert.FileExists(t, checkpoint)
+		assert.Len(t, server.Objects(appId, "houses"), 4)
+		server.FailWhen(nil)
+
+		report := restoreTo(t, appId, RestoreOptions{NewGuids: true, Checkpoint: checkpoint})
+		assert.Len(t, report.Guids, 10)
+		assert.Len(t, server.Objects(appId, "houses"), 5)
+		assert.Len(t, server.Objects(appId, "room"), 5)
+		assert.NoFileExists(t, checkpoint)
+	})
+
+	t.Run("resume after a lost response", func(t *testing.T) {
+		var (
+			appId      = "lost_app_id"
+			checkpoint = filepath.Join(t.TempDir(), "restore.checkpoint")
+			requests   int
+		)
+
+		// the third houses batch is written but its response is lost
+		lossy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut || !strings.Contains(r.URL.Path, "multiple-update/houses") {
+				server.ServeHTTP(w, r)
+				return
+			}
+			requests++
+			if requests != 3 {
+				server.ServeHTTP(w, r)
+				return
+			}
+			server.ServeHTTP(httptest.NewRecorder(), r)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer lossy.Close()
+
+		lossyApi := ucodesdk.New(&ucodesdk.Config{BaseURL: lossy.URL, AppId: source})
+		_, err := Restore(ctx, lossyApi, bytes.NewReader(archive.Bytes()), int64(archive.Len()), RestoreOptions{AppId: appId, BatchSize: 2, NewGuids: true, Checkpoint: checkpoint})
+		assert.Error(t, err)
+		assert.Len(t, server.Objects(appId, "houses"), 5)
+
+		report := restoreWith(t, lossyApi, appId, RestoreOptions{NewGuids: true, Checkpoint: checkpoint})
+		houses := server.Objects(appId, "houses")
+		assert.Len(t, houses, 5)
+		assert.Equal(t, report.Guids["house-4"], houses[4]["guid"])
+	})
+
+	t.Run("checkpoint of another app", func(t *testing.T) {
+		var (
+			appId      = "first_app_id"
+			checkpoint = filepath.Join(t.TempDir(), "restore.checkpoint")
+		)
+
+		server.FailWhen(func(method, path string, body []byte) bool {
+			return method == http.MethodPut && strings.Contains(path, "multiple-update/houses")
+		})
+		_, err := Restore(ctx, ucodeApi, bytes.NewReader(archive.Bytes()), int64(archive.Len()), RestoreOptions{AppId: appId, BatchSize: 2, Checkpoint: checkpoint})
+		assert.Error(t, err)
+		assert.FileExists(t, checkpoint)
+		server.FailWhen(nil)
+
+		_, err = Restore(ctx, ucodeApi, bytes.NewReader(archive.Bytes()), int64(archive.Len()), RestoreOptions{AppId: "second_app_id", BatchSize: 2, Checkpoint: checkpoint})
+		assert.ErrorContains(t, err, "belongs to a restore into app first_app_id")
+		assert.Empty(t, server.Objects("second_app_id", "room"))
+
+		otherServer := ucodesdk.New(&ucodesdk.Config{BaseURL: "http://127.0.0.1:1", AppId: appId})
+		_, err = Restore(ctx, otherServer, bytes.NewReader(archive.Bytes()), int64(archive.Len()), RestoreOptions{BatchSize: 2, Checkpoint: checkpoint})
+		assert.ErrorContains(t, err, "belongs to a restore into app first_app_id at "+server.URL)
+
+		// the app id of the config is the target when RestoreOptions.AppId is empty
+		_, err = Restore(ctx, server.Client(appId), bytes.NewReader(archive.Bytes()), int64(archive.Len()), RestoreOptions{BatchSize: 2, Checkpoint: checkpoint})
+		assert.NoError(t, err)
+		assert.Len(t, server.Objects(appId, "houses"), 5)
+
+		restoreTo(t, "second_app_id", RestoreOptions{Checkpoint: checkpoint})
+		assert.Len(t, server.Objects("second_app_id", "room"), 5)
+	})
+
+	t.Run("invalid archive", func(t *testing.T) {
+		_, err := Restore(ctx, ucodeApi, strings.NewReader("not a zip"), 9, RestoreOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,402 @@
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/spf13/cast"
+)
+
+const defaultBatchSize = 100
+
+type (
+	RestoreOptions struct {
+		// AppId is the app to restore into, the app id of the client config by default.
+		AppId       string
+		DisableFaas bool
+		// BatchSize is the number of objects sent in one MultipleUpdate request, 100 by default.
+		BatchSize int
+		/*
+			NewGuids restores the objects with new guids instead of the ones in the archive,
+			e.g. to copy data into an app which already has them. References to restored
+			objects are rewritten, see Restore.
+		*/
+		NewGuids bool
+		/*
+			Checkpoint is the path of the file the progress is saved to after every batch,
+			and before it with NewGuids, so a resumed run creates its objects with the same guids.
+			When it exists, Restore continues where the interrupted run stopped, sending the
+			batch it was at with the same idempotency key (see ucodesdk.WithIdempotencyKey).
+			A checkpoint of another archive, target app or base url is an error.
+			It is removed after a successful restore.
+		*/
+		Checkpoint string
+	}
+
+	RestoreReport struct {
+		// Tables is the number of restored objects per table, including the ones of earlier runs.
+		Tables map[string]int
+		// Links is the number of restored links, including the ones of earlier runs.
+		Links int
+		// Guids maps the guids of the archive to the new guids when RestoreOptions.NewGuids is set.
+		Guids map[string]string
+	}
+
+	// checkpoint is the progress of a restore.
+	checkpoint struct {
+		// Id is generated for a new checkpoint, the batches of the restore are sent with idempotency keys derived from it.
+		Id string `json:"id"`
+		// AppId and CreatedAt identify the archive.
+		AppId     string    `json:"app_id"`
+		CreatedAt time.Time `json:"created_at"`
+		// TargetAppId and BaseURL identify the app restored into.
+		TargetAppId string            `json:"target_app_id"`
+		BaseURL     string            `json:"base_url"`
+		Tables      map[string]int    `json:"tables"`
+		Links       map[string]int    `json:"links"`
+		Guids       map[string]string `json:"guids"`
+	}
+)
+
+/*
+Restore replays the archive of size bytes read from r into the app.
+
+Tables are restored in manifest order with CreateMany, which sends
+MultipleUpdate requests, then the links are restored with AppendManyToMany.
+The guids of the archive are kept unless opts.NewGuids is set. With new guids,
+string fields equal to the guid of an already restored object are rewritten to
+its new guid, so relation fields resolve when the referenced table comes first
+in the archive.
+*/
+func Restore(ctx context.Context, ucodeApi ucodesdk.UcodeApis, r io.ReaderAt, size int64, opts RestoreOptions) (RestoreReport, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return RestoreReport{}, err
+	}
+
+	manifest, err := ReadManifest(archive)
+	if err != nil {
+		return RestoreReport{}, err
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+
+	target := checkpoint{AppId: manifest.AppId, CreatedAt: manifest.CreatedAt, TargetAppId: opts.AppId, BaseURL: ucodeApi.Config().BaseURL}
+	if target.TargetAppId == "" {
+		target.TargetAppId = ucodeApi.Config().AppId
+	}
+
+	progress, err := loadCheckpoint(opts.Checkpoint, target)
+	if err != nil {
+		return RestoreReport{}, err
+	}
+
+	err = restore(ctx, ucodeApi, archive, manifest, progress, opts)
+	report := progress.report()
+	if err != nil {
+		if saveErr := progress.save(opts.Checkpoint); saveErr != nil {
+			return report, errors.Join(err, saveErr)
+		}
+		return report, err
+	}
+
+	if opts.Checkpoint != "" {
+		if err = os.Remove(opts.Checkpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// ReadManifest reads the manifest of the archive and checks its version.
+func ReadManifest(archive *zip.Reader) (Manifest, error) {
+	var manifest Manifest
+
+	file, err := archive.Open(manifestName)
+	if err != nil {
+		return manifest, fmt.Errorf("not a complete backup archive: %w", err)
+	}
+	defer file.Close()
+
+	if err = json.NewDecoder(file).Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	if manifest.Version < 1 || manifest.Version > Version {
+		return manifest, fmt.Errorf("unsupported archive version %d, expected %d", manifest.Version, Version)
+	}
+
+	return manifest, nil
+}
+
+func restore(ctx context.Context, ucodeApi ucodesdk.UcodeApis, archive *zip.Reader, manifest Manifest, progress *checkpoint, opts RestoreOptions) error {
+	// link fields are restored by AppendManyToMany once the linked objects exist
+	linkFields := map[string][]string{}
+	for _, link := range manifest.Links {
+		linkFields[link.TableFrom] = append(linkFields[link.TableFrom], link.field())
+	}
+
+	bulkOptions := &ucodesdk.BulkOptions{
+		AppId:       opts.AppId,
+		DisableFaas: opts.DisableFaas,
+		ChunkSize:   opts.BatchSize,
+		Concurrency: 1,
+	}
+
+	for _, table := range manifest.Tables {
+		var batch []map[string]interface{}
+
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+
+			if opts.NewGuids {
+				// the new guids are saved before sending, so a resumed run sends the batch with the same guids
+				for _, object := range batch {
+					guid := cast.ToString(object["guid"])
+					if _, ok := progress.Guids[guid]; !ok && guid != "" {
+						progress.Guids[guid] = ucodesdk.NewGUID()
+					}
+				}
+				if err := progress.save(opts.Checkpoint); err != nil {
+					return err
+				}
+			}
+
+			objects := make([]map[string]interface{}, len(batch))
+			for i, object := range batch {
+				objects[i] = progress.prepare(object, linkFields[table.TableSlug])
+			}
+
+			// a batch sent again after its response was lost has the same key, so the server does not create it twice
+			done := progress.Tables[table.TableSlug]
+			batchCtx := ucodesdk.WithIdempotencyKey(ctx, fmt.Sprintf("restore:%s:%s:%d-%d", progress.Id, table.TableSlug, done, done+len(batch)))
+
+			if _, err := ucodeApi.Bulk().CreateMany(batchCtx, table.TableSlug, objects, bulkOptions); err != nil {
+				return fmt.Errorf("restoring %s after %d objects: %w", table.TableSlug, progress.Tables[table.TableSlug], err)
+			}
+
+			progress.Tables[table.TableSlug] += len(batch)
+			batch = batch[:0]
+
+			return progress.save(opts.Checkpoint)
+		}
+
+		err := readLines(archive, tablePath(table.TableSlug), progress.Tables[table.TableSlug], func(decoder *json.Decoder) error {
+			var object map[string]interface{}
+			if err := decoder.Decode(&object); err != nil {
+				return err
+			}
+
+			batch = append(batch, object)
+			if len(batch) < opts.BatchSize {
+				return nil
+			}
+			return flush()
+		})
+		if err == nil {
+			err = flush()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, link := range manifest.Links {
+		key := link.String()
+
+		err := readLines(archive, linkPath(link.Link), progress.Links[key], func(decoder *json.Decoder) error {
+			var record linkRecord
+			if err := decoder.Decode(&record); err != nil {
+				return err
+			}
+
+			idTo := make([]string, len(record.IdTo))
+			for i, id := range record.IdTo {
+				idTo[i] = cast.ToString(progress.remap(id))
+			}
+
+			_, err := ucodeApi.AppendManyToMany(&ucodesdk.Argument{
+				AppId:     opts.AppId,
+				TableSlug: link.TableFrom,
+				Request: ucodesdk.Request{Data: map[string]interface{}{
+					"table_from": link.TableFrom,
+					"table_to":   link.TableTo,
+					"id_from":    progress.remap(record.IdFrom),
+					"id_to":      idTo,
+				}},
+				DisableFaas: opts.DisableFaas,
+			})
+			if err != nil {
+				return fmt.Errorf("restoring link %s of %s: %w", key, record.IdFrom, err)
+			}
+
+			progress.Links[key]++
+			if progress.Links[key]%opts.BatchSize == 0 {
+				return progress.save(opts.Checkpoint)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readLines opens the archive file and calls fn for every JSON line after the first skip ones.
+func readLines(archive *zip.Reader, name string, skip int, fn func(decoder *json.Decoder) error) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+
+	for i := 0; decoder.More(); i++ {
+		if i < skip {
+			var discard json.RawMessage
+			if err = decoder.Decode(&discard); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			continue
+		}
+
+		if err = fn(decoder); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadCheckpoint reads the checkpoint at path, or starts a new one, of the restore of the archive into the app of target.
+func loadCheckpoint(path string, target checkpoint) (*checkpoint, error) {
+	progress := &checkpoint{
+		Id:          ucodesdk.NewGUID(),
+		AppId:       target.AppId,
+		CreatedAt:   target.CreatedAt,
+		TargetAppId: target.TargetAppId,
+		BaseURL:     target.BaseURL,
+		Tables:      map[string]int{},
+		Links:       map[string]int{},
+		Guids:       map[string]string{},
+	}
+	if path == "" {
+		return progress, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return progress, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved checkpoint
+	if err = json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+
+	if saved.AppId != target.AppId || !saved.CreatedAt.Equal(target.CreatedAt) {
+		return nil, fmt.Errorf("checkpoint %s belongs to another archive", path)
+	}
+	if saved.TargetAppId != target.TargetAppId || saved.BaseURL != target.BaseURL {
+		return nil, fmt.Errorf("checkpoint %s belongs to a restore into app %s at %s", path, saved.TargetAppId, saved.BaseURL)
+	}
+
+	if saved.Id != "" {
+		progress.Id = saved.Id
+	}
+	for key, value := range saved.Tables {
+		progress.Tables[key] = value
+	}
+	for key, value := range saved.Links {
+		progress.Links[key] = value
+	}
+	for key, value := range saved.Guids {
+		progress.Guids[key] = value
+	}
+
+	return progress, nil
+}
+
+func (c *checkpoint) report() RestoreReport {
+	report := RestoreReport{Tables: c.Tables}
+	for _, count := range c.Links {
+		report.Links += count
+	}
+	if len(c.Guids) > 0 {
+		report.Guids = c.Guids
+	}
+
+	return report
+}
+
+// save writes the checkpoint to a temporary file first, so an interruption never leaves it half written.
+func (c *checkpoint) save(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+/*
+prepare returns the object to create: link fields are dropped and references to restored
+objects remapped, as is its own guid when it was given a new one.
+*/
+func (c *checkpoint) prepare(object map[string]interface{}, linkFields []string) map[string]interface{} {
+	prepared := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		prepared[key] = c.remap(value)
+	}
+
+	for _, field := range linkFields {
+		delete(prepared, field)
+	}
+
+	return prepared
+}
+
+// remap replaces the guids of restored objects in a string or a list of strings.
+func (c *checkpoint) remap(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if guid, ok := c.Guids[v]; ok {
+			return guid
+		}
+	case []interface{}:
+		remapped := make([]interface{}, len(v))
+		for i, item := range v {
+			remapped[i] = c.remap(item)
+		}
+		return remapped
+	}
+
+	return value
+}
@@ -0,0 +1,387 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	defaultChunkSize   = 100
+	defaultConcurrency = 4
+)
+
+var (
+	// ErrNotSent is the error of items skipped because an earlier chunk failed and BulkOptions.StopOnError is set.
+	ErrNotSent = errors.New("not sent, an earlier chunk failed")
+	// ErrMissingGuid is the error of UpdateMany items without a guid.
+	ErrMissingGuid = errors.New("guid is required")
+)
+
+type (
+	// BulkApis works on many objects at once, see UcodeApis.Bulk.
+	BulkApis interface {
+		/*
+			CreateMany is a function that creates many objects with as few requests as possible
+
+			objects are split into chunks of opts.ChunkSize which are sent as MultipleUpdate
+			requests of new objects, opts.Concurrency at once.
+			A result is returned for every object in input order, with its guid or error,
+			and the error is a *BulkError when any object failed, so only the failed ones can be retried.
+
+			Works for [Mongo, Postgres]
+		*/
+		CreateMany(ctx context.Context, tableSlug string, objects []map[string]interface{}, opts *BulkOptions) (BulkResults, error)
+		/*
+			UpdateMany is a function that updates many objects like MultipleUpdate, split into chunks
+
+			Every object must have its guid. A result is returned for every object in input order
+			and the error is a *BulkError when any object failed.
+			With opts.StopOnError no chunk is sent after the first failed one.
+
+			Works for [Mongo, Postgres]
+		*/
+		UpdateMany(ctx context.Context, tableSlug string, objects []map[string]interface{}, opts *BulkOptions) (BulkResults, error)
+		/*
+			DeleteMany is a function that deletes many objects like MultipleDelete, split into chunks
+
+			A result is returned for every id in input order and the error is a *BulkError when any id failed.
+			With opts.StopOnError no chunk is sent after the first failed one.
+
+			Works for [Mongo, Postgres]
+		*/
+		DeleteMany(ctx context.Context, tableSlug string, ids []string, opts *BulkOptions) (BulkResults, error)
+		/*
+			Upsert is a function that updates the object matching the key fields or creates it when there is none
+
+			The existing object is looked up with GetListSlim by the values of keyFields in object,
+			its guid is carried to the update. Result.Action tells which path was taken.
+			ErrDuplicateKey is returned, and nothing is written, when more than one object matches.
+
+			Works for [Mongo, Postgres]
+		*/
+		Upsert(ctx context.Context, tableSlug string, keyFields []string, object map[string]interface{}, opts *BulkOptions) (UpsertResult, error)
+		/*
+			UpsertMany is a function that upserts many objects like Upsert
+
+			Lookups run opts.Concurrency at once, creates and updates are sent with CreateMany and UpdateMany.
+			Objects of the input sharing a key with an earlier one fail with ErrDuplicateKey.
+			The error is a *BulkError when any object failed.
+
+			Works for [Mongo, Postgres]
+		*/
+		UpsertMany(ctx context.Context, tableSlug string, keyFields []string, objects []map[string]interface{}, opts *BulkOptions) ([]UpsertResult, error)
+		/*
+			GetListStream is a function that gets a list of objects like GetList without holding the page in memory
+
+			The response is decoded object by object and fn is called with every object as it arrives,
+			the number of decoded objects is returned. An error returned by fn stops the decoding.
+			Unlike GetList, the status code is checked and arg is not modified.
+
+			Works for [Mongo, Postgres]
+		*/
+		GetListStream(ctx context.Context, arg *ArgumentWithPegination, fn func(object map[string]interface{}) error) (int, error)
+		/*
+			GetListSlimStream is a function that gets a list of objects like GetListSlim, decoding them one by one like GetListStream
+
+			Works for [Mongo, Postgres]
+		*/
+		GetListSlimStream(ctx context.Context, arg *ArgumentWithPegination, fn func(object map[string]interface{}) error) (int, error)
+	}
+
+	// BulkOptions configures the bulk helpers. A nil *BulkOptions uses the defaults.
+	BulkOptions struct {
+		AppId       string
+		DisableFaas bool
+		// ChunkSize is the number of objects sent in one request, 100 by default.
+		ChunkSize int
+		// Concurrency is the number of requests sent at once, 4 by default.
+		Concurrency int
+		// StopOnError stops sending chunks after the first failed one, the items not sent get ErrNotSent.
+		StopOnError bool
+	}
+
+	// BulkResult is the outcome of one item of a bulk call.
+	BulkResult struct {
+		// Index is the position of the item in the input.
+		Index int
+		Guid  string
+		Err   error
+	}
+
+	BulkResults []BulkResult
+
+	// BulkError is returned when some items of a bulk call failed. Their errors are in the results.
+	BulkError struct {
+		Total  int
+		Failed int
+		// Err is the error of the first failed item.
+		Err error
+	}
+)
+
+func (o *object) Bulk() BulkApis {
+	return o
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("%d of %d items failed: %v", e.Failed, e.Total, e.Err)
+}
+
+func (e *BulkError) Unwrap() error {
+	return e.Err
+}
+
+// Failed returns the results of the failed items, use their Index to retry them.
+func (r BulkResults) Failed() BulkResults {
+	var failed BulkResults
+	for _, result := range r {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	return failed
+}
+
+// Guids returns the guids of the succeeded items in input order.
+func (r BulkResults) Guids() []string {
+	var guids []string
+	for _, result := range r {
+		if result.Err == nil {
+			guids = append(guids, result.Guid)
+		}
+	}
+
+	return guids
+}
+
+// err returns a *BulkError when any item failed.
+func (r BulkResults) err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return &BulkError{Total: len(r), Failed: len(failed), Err: failed[0].Err}
+}
+
+func (opts *BulkOptions) withDefaults() BulkOptions {
+	var result BulkOptions
+	if opts != nil {
+		result = *opts
+	}
+
+	if result.ChunkSize <= 0 {
+		result.ChunkSize = defaultChunkSize
+	}
+	if result.Concurrency <= 0 {
+		result.Concurrency = defaultConcurrency
+	}
+
+	return result
+}
+
+func (o *object) CreateMany(ctx context.Context, tableSlug string, objects []map[string]interface{}, opts *BulkOptions) (BulkResults, error) {
+	var (
+		options  = opts.withDefaults()
+		results  = make(BulkResults, len(objects))
+		prepared = make([]map[string]interface{}, len(objects))
+	)
+
+	// guids are generated here so every item can be reported even if the server does not echo them
+	for i, object := range objects {
+		item := make(map[string]interface{}, len(object)+2)
+		for key, value := range object {
+			item[key] = value
+		}
+
+		guid, _ := item["guid"].(string)
+		if guid == "" {
+			guid = NewGUID()
+		}
+		item["guid"] = guid
+		item["is_new"] = true
+
+		prepared[i] = item
+		results[i] = BulkResult{Index: i, Guid: guid}
+	}
+
+	o.runChunks(ctx, len(prepared), options, results, func(ctx context.Context, start, end int) error {
+		return o.multipleUpdateChunk(ctx, tableSlug, prepared[start:end], options)
+	})
+
+	return results, results.err()
+}
+
+func (o *object) UpdateMany(ctx context.Context, tableSlug string, objects []map[string]interface{}, opts *BulkOptions) (BulkResults, error) {
+	var (
+		options = opts.withDefaults()
+		results = make(BulkResults, len(objects))
+		valid   []map[string]interface{}
+		indexes []int
+	)
+
+	// objects without a guid fail before anything is sent
+	for i, object := range objects {
+		guid, _ := object["guid"].(string)
+		results[i] = BulkResult{Index: i, Guid: guid}
+
+		if guid == "" {
+			results[i].Err = ErrMissingGuid
+			continue
+		}
+
+		valid = append(valid, object)
+		indexes = append(indexes, i)
+	}
+
+	sent := make(BulkResults, len(valid))
+	o.runChunks(ctx, len(valid), options, sent, func(ctx context.Context, start, end int) error {
+		return o.multipleUpdateChunk(ctx, tableSlug, valid[start:end], options)
+	})
+
+	for i, result := range sent {
+		results[indexes[i]].Err = result.Err
+	}
+
+	return results, results.err()
+}
+
+func (o *object) DeleteMany(ctx context.Context, tableSlug string, ids []string, opts *BulkOptions) (BulkResults, error) {
+	var (
+		options = opts.withDefaults()
+		results = make(BulkResults, len(ids))
+	)
+
+	for i, id := range ids {
+		results[i] = BulkResult{Index: i, Guid: id}
+	}
+
+	o.runChunks(ctx, len(ids), options, results, func(ctx context.Context, start, end int) error {
+		return o.multipleDeleteChunk(ctx, tableSlug, ids[start:end], options)
+	})
+
+	return results, results.err()
+}
+
+/*
+runChunks calls send for every chunk of n items with at most options.Concurrency calls at once
+and stores the error of a failed chunk in the results of all its items.
+*/
+func (o *object) runChunks(ctx context.Context, n int, options BulkOptions, results BulkResults, send func(ctx context.Context, start, end int) error) {
+	var (
+		wg        sync.WaitGroup
+		failed    atomic.Bool
+		semaphore = make(chan struct{}, options.Concurrency)
+	)
+
+	for start := 0; start < n; start += options.ChunkSize {
+		end := start + options.ChunkSize
+		if end > n {
+			end = n
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		var skipErr error
+		switch {
+		case ctx.Err() != nil:
+			skipErr = ctx.Err()
+		case options.StopOnError && failed.Load():
+			skipErr = ErrNotSent
+		}
+		if skipErr != nil {
+			for i := start; i < n; i++ {
+				results[i].Err = skipErr
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			if err := send(ctx, start, end); err != nil {
+				failed.Store(true)
+				for i := start; i < end; i++ {
+					results[i].Err = err
+				}
+			}
+		}(start, end)
+	}
+
+	wg.Wait()
+}
+
+// multipleUpdateChunk sends one MultipleUpdate request and checks its status code.
+func (o *object) multipleUpdateChunk(ctx context.Context, tableSlug string, objects []map[string]interface{}, options BulkOptions) error {
+	var (
+		url      = fmt.Sprintf("%s/v1/object/multiple-update/%s?from-ofs=%t", o.config.BaseURL, tableSlug, options.DisableFaas)
+		response ClientApiMultipleUpdateResponse
+	)
+
+	defer o.invalidate(options.AppId, tableSlug)
+
+	statusCode, respByte, err := o.sendIdempotent(ctx, url, http.MethodPut, Request{Data: map[string]interface{}{"objects": objects}}, o.headers(options.AppId), chunkKey(ctx, objects), nil)
+	if err != nil {
+		return err
+	}
+
+	if statusCode >= http.StatusBadRequest {
+		return &HTTPError{StatusCode: statusCode, Body: respByte}
+	}
+
+	return json.Unmarshal(respByte, &response)
+}
+
+// chunkKey derives the idempotency key of a chunk from the key of ctx and the guid of its first object.
+func chunkKey(ctx context.Context, objects []map[string]interface{}) string {
+	key := IdempotencyKey(ctx)
+	if key == "" || len(objects) == 0 {
+		return ""
+	}
+
+	guid, _ := objects[0]["guid"].(string)
+	return key + ":" + guid
+}
+
+// multipleDeleteChunk sends one MultipleDelete request and checks its status code.
+func (o *object) multipleDeleteChunk(ctx context.Context, tableSlug string, ids []string, options BulkOptions) error {
+	url := fmt.Sprintf("%s/v1/object/%s/?from-ofs=%t", o.config.BaseURL, tableSlug, options.DisableFaas)
+
+	defer o.invalidate(options.AppId, tableSlug)
+
+	statusCode, respByte, err := o.send(ctx, url, http.MethodDelete, map[string]interface{}{"ids": ids}, o.headers(options.AppId))
+	if err != nil {
+		return err
+	}
+
+	if statusCode >= http.StatusBadRequest {
+		return &HTTPError{StatusCode: statusCode, Body: respByte}
+	}
+
+	return nil
+}
+
+// headers returns the authorization headers of the app, the app id of the config is used when appId is empty.
+func (o *object) headers(appId string) map[string]string {
+	if appId == "" {
+		appId = o.config.AppId
+	}
+
+	return map[string]string{
+		"authorization": "API-KEY",
+		"X-API-KEY":     appId,
+	}
+}
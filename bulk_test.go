@@ -0,0 +1,171 @@
+package ucodesdk_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMany(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId)
+		houses   = make([]map[string]interface{}, 250)
+	)
+	for i := range houses {
+		houses[i] = map[string]interface{}{"name": fmt.Sprintf("house_%d", i), "price": 15000}
+	}
+
+	// the chunk holding house_120 fails
+	server.FailWhen(func(method, path string, body []byte) bool {
+		return bytes.Contains(body, []byte(`"house_120"`))
+	})
+
+	results, err := ucodeApi.Bulk().CreateMany(context.Background(), "houses", houses, &ucodesdk.BulkOptions{ChunkSize: 100, Concurrency: 2})
+
+	var bulkErr *ucodesdk.BulkError
+	if assert.ErrorAs(t, err, &bulkErr) {
+		assert.Equal(t, 250, bulkErr.Total)
+		assert.Equal(t, 100, bulkErr.Failed)
+	}
+	assert.Len(t, results, 250)
+	assert.Len(t, server.Objects(appId, "houses"), 150)
+
+	failed := results.Failed()
+	assert.Equal(t, 100, failed[0].Index)
+	assert.Equal(t, 199, failed[len(failed)-1].Index)
+
+	// retry the failed items only
+	server.FailWhen(nil)
+
+	retry := make([]map[string]interface{}, 0, len(failed))
+	for _, result := range failed {
+		retry = append(retry, houses[result.Index])
+	}
+
+	results, err = ucodeApi.Bulk().CreateMany(context.Background(), "houses", retry, nil)
+	assert.NoError(t, err)
+	assert.Len(t, results.Guids(), 100)
+
+	stored := server.Objects(appId, "houses")
+	assert.Len(t, stored, 250)
+	for _, house := range stored {
+		assert.NotContains(t, house, "is_new")
+	}
+}
+
+func TestUpdateManyDeleteMany(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId)
+		houses   = make([]map[string]interface{}, 30)
+	)
+	for i := range houses {
+		houses[i] = map[string]interface{}{"name": fmt.Sprintf("house_%d", i)}
+	}
+	guids := server.Seed(appId, "houses", houses...)
+
+	updates := make([]map[string]interface{}, 0, len(guids)+1)
+	for _, guid := range guids {
+		updates = append(updates, map[string]interface{}{"guid": guid, "price": 20000})
+	}
+	updates = append(updates, map[string]interface{}{"price": 20000})
+
+	// the second chunk fails, the third one is never sent
+	server.FailWhen(func(method, path string, body []byte) bool {
+		return bytes.Contains(body, []byte(guids[15]))
+	})
+
+	results, err := ucodeApi.Bulk().UpdateMany(context.Background(), "houses", updates, &ucodesdk.BulkOptions{ChunkSize: 10, Concurrency: 1, StopOnError: true})
+	assert.Error(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.IsType(t, &ucodesdk.HTTPError{}, results[10].Err)
+	assert.ErrorIs(t, results[20].Err, ucodesdk.ErrNotSent)
+	assert.ErrorIs(t, results[30].Err, ucodesdk.ErrMissingGuid)
+
+	stored := server.Objects(appId, "houses")
+	assert.EqualValues(t, 20000, stored[9]["price"])
+	assert.Nil(t, stored[10]["price"])
+
+	server.FailWhen(nil)
+
+	results, err = ucodeApi.Bulk().DeleteMany(context.Background(), "houses", guids, &ucodesdk.BulkOptions{ChunkSize: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, guids, results.Guids())
+	assert.Empty(t, server.Objects(appId, "houses"))
+}
+
+func TestUpsertMany(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId)
+		ctx      = context.Background()
+	)
+	guids := server.Seed(appId, "houses",
+		map[string]interface{}{"external_id": "1", "name": "old"},
+		map[string]interface{}{"external_id": "3"},
+		map[string]interface{}{"external_id": "3"},
+	)
+
+	results, err := ucodeApi.Bulk().UpsertMany(ctx, "houses", []string{"external_id"}, []map[string]interface{}{
+		{"external_id": "1", "name": "new"},
+		{"external_id": "4", "name": "created"},
+		{"external_id": "3"},
+		{"external_id": "4"},
+		{"name": "no key"},
+	}, nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, ucodesdk.UpsertUpdated, results[0].Action)
+	assert.Equal(t, guids[0], results[0].Guid)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, ucodesdk.UpsertCreated, results[1].Action)
+	assert.NotEmpty(t, results[1].Guid)
+	assert.NoError(t, results[1].Err)
+	assert.ErrorIs(t, results[2].Err, ucodesdk.ErrDuplicateKey)
+	assert.ErrorIs(t, results[3].Err, ucodesdk.ErrDuplicateKey)
+	assert.ErrorIs(t, results[4].Err, ucodesdk.ErrMissingKey)
+
+	stored := server.Objects(appId, "houses")
+	assert.Len(t, stored, 4)
+	assert.Equal(t, "new", stored[0]["name"])
+
+	result, err := ucodeApi.Bulk().Upsert(ctx, "houses", []string{"external_id"}, map[string]interface{}{"external_id": "4", "name": "updated"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ucodesdk.UpsertUpdated, result.Action)
+	assert.Equal(t, results[1].Guid, result.Guid)
+}
+
+func TestGetListSlimEscapesFilter(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId)
+	)
+	server.Seed(appId, "houses", map[string]interface{}{"name": "a&b#c d+e"}, map[string]interface{}{"name": "a"})
+
+	list, _, err := ucodeApi.GetListSlim(&ucodesdk.ArgumentWithPegination{
+		TableSlug: "houses",
+		Request:   ucodesdk.Request{Data: map[string]interface{}{"name": "a&b#c d+e"}},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Data.Data.Response, 1) {
+		assert.Equal(t, "a&b#c d+e", list.Data.Data.Response[0]["name"])
+	}
+}
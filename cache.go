@@ -0,0 +1,206 @@
+package ucodesdk
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheTTL  = time.Minute
+	defaultCacheSize = 1000
+)
+
+/*
+Cache stores responses of read requests with Request.IsCached set.
+
+Keys are derived from the app id, table slug, request and the write generation
+of the table, so values never have to be deleted: a write through any client
+moves the table to a new generation and the old values expire.
+Implementations must be safe for concurrent use.
+*/
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+var (
+	// defaultCache is shared by the clients without Config.Cache, functions usually create a client per request.
+	defaultCache = NewMemoryCache(0)
+	// tableGenerations is shared by all clients, so a write through one of them invalidates the reads cached by the others.
+	tableGenerations generations
+)
+
+type (
+	// MemoryCache is an in-memory Cache dropping the least recently used values when it is full.
+	MemoryCache struct {
+		mu      sync.Mutex
+		size    int
+		order   *list.List
+		entries map[string]*list.Element
+	}
+
+	cacheEntry struct {
+		key       string
+		value     []byte
+		expiresAt time.Time
+	}
+
+	// generations counts the writes to every table of every app, it is part of the cache keys.
+	generations struct {
+		mu     sync.Mutex
+		counts map[string]uint64
+	}
+)
+
+// NewMemoryCache returns a MemoryCache holding at most size values, 1000 when size is not positive.
+func NewMemoryCache(size int) *MemoryCache {
+	if size <= 0 {
+		size = defaultCacheSize
+	}
+
+	return &MemoryCache{
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = &cacheEntry{key: key, value: value, expiresAt: time.Now().Add(ttl)}
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, value: value, expiresAt: time.Now().Add(ttl)})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of stored values, expired ones included until they are read or evicted.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (g *generations) get(appId, tableSlug string) uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.counts[appId+"/"+tableSlug]
+}
+
+func (g *generations) bump(appId, tableSlug string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.counts == nil {
+		g.counts = map[string]uint64{}
+	}
+	g.counts[appId+"/"+tableSlug]++
+}
+
+/*
+read sends a read request of the table, through the cache when cached is set
+and coalesced with identical reads in flight unless coalesce is false.
+Only successful responses are cached.
+*/
+func (o *object) read(ctx context.Context, appId, tableSlug, url, method string, body interface{}, headers map[string]string, cached, coalesce bool) (int, []byte, error) {
+	if !cached {
+		return o.fetch(ctx, appId, tableSlug, url, method, body, headers, coalesce)
+	}
+
+	key, err := o.cacheKey(appId, tableSlug, url, method, body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if value, ok := o.cache().Get(key); ok {
+		o.readCounters.cacheHits.Add(1)
+		return http.StatusOK, value, nil
+	}
+
+	statusCode, respByte, err := o.fetch(ctx, appId, tableSlug, url, method, body, headers, coalesce)
+	if err == nil && statusCode < http.StatusBadRequest {
+		ttl := o.config.CacheTTL
+		if ttl <= 0 {
+			ttl = defaultCacheTTL
+		}
+		o.cache().Set(key, respByte, ttl)
+	}
+
+	return statusCode, respByte, err
+}
+
+// cache returns Config.Cache, read on every call so it can be set after New, or the shared default cache.
+func (o *object) cache() Cache {
+	if o.config.Cache != nil {
+		return o.config.Cache
+	}
+
+	return defaultCache
+}
+
+// cacheKey hashes the app id, table slug and its generation, and the request; maps in the body are marshaled with sorted keys.
+func (o *object) cacheKey(appId, tableSlug, url, method string, body interface{}) (string, error) {
+	if appId == "" {
+		appId = o.config.AppId
+	}
+
+	bodyByte, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%s %s\x00%s", appId, tableSlug, tableGenerations.get(appId, tableSlug), method, url, bodyByte)))
+	return "ucode:" + hex.EncodeToString(sum[:]), nil
+}
+
+// invalidate moves the tables to a new generation after a write, appId defaults to the app id of the config.
+func (o *object) invalidate(appId string, tableSlugs ...string) {
+	if appId == "" {
+		appId = o.config.AppId
+	}
+
+	for _, tableSlug := range tableSlugs {
+		if tableSlug != "" {
+			tableGenerations.bump(appId, tableSlug)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package ucodesdk_test
+
+import (
+	"testing"
+	"time"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCache(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId)
+		guids    = server.Seed(appId, "settings", map[string]interface{}{"key": "currency", "value": "UZS"})
+	)
+	server.Seed(appId, "houses", map[string]interface{}{"name": "house"})
+
+	getSetting := func(cached bool) string {
+		setting, _, err := ucodeApi.GetSingleSlim(&ucodesdk.Argument{
+			TableSlug: "settings",
+			Request:   ucodesdk.Request{Data: map[string]interface{}{"guid": guids[0]}, IsCached: cached},
+		})
+		assert.NoError(t, err)
+		return setting.Data.Data.Response["value"].(string)
+	}
+	requests := func() int {
+		return len(server.Requests())
+	}
+
+	assert.Equal(t, "UZS", getSetting(true))
+	assert.Equal(t, "UZS", getSetting(true))
+	assert.Equal(t, 1, requests())
+
+	// not cached reads always go to the server
+	getSetting(false)
+	assert.Equal(t, 2, requests())
+
+	// writes to other tables keep the cache
+	_, _, err := ucodeApi.CreateObject(&ucodesdk.Argument{TableSlug: "houses", Request: ucodesdk.Request{Data: map[string]interface{}{"name": "new"}}})
+	assert.NoError(t, err)
+	getSetting(true)
+	assert.Equal(t, 3, requests())
+
+	// writes to the table invalidate it
+	_, _, err = ucodeApi.UpdateObject(&ucodesdk.Argument{TableSlug: "settings", Request: ucodesdk.Request{Data: map[string]interface{}{"guid": guids[0], "value": "USD"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", getSetting(true))
+	assert.Equal(t, 5, requests())
+
+	t.Run("list filters are keys", func(t *testing.T) {
+		list := func(name string) int {
+			houses, _, err := ucodeApi.GetListSlim(&ucodesdk.ArgumentWithPegination{
+				TableSlug: "houses",
+				Request:   ucodesdk.Request{Data: map[string]interface{}{"name": name}, IsCached: true},
+			})
+			assert.NoError(t, err)
+			return len(houses.Data.Data.Response)
+		}
+
+		before := requests()
+		assert.Equal(t, 1, list("house"))
+		assert.Equal(t, 1, list("new"))
+		assert.Equal(t, 1, list("house"))
+		assert.Equal(t, before+2, requests())
+	})
+}
+
+func TestReadCacheShared(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId = "shared_app_id"
+		guids = server.Seed(appId, "settings", map[string]interface{}{"key": "currency", "value": "UZS"})
+	)
+
+	getSetting := func(ucodeApi ucodesdk.UcodeApis) string {
+		setting, _, err := ucodeApi.GetSingleSlim(&ucodesdk.Argument{
+			TableSlug: "settings",
+			Request:   ucodesdk.Request{Data: map[string]interface{}{"guid": guids[0]}, IsCached: true},
+		})
+		assert.NoError(t, err)
+		return setting.Data.Data.Response["value"].(string)
+	}
+
+	// clients created per request share the default cache
+	assert.Equal(t, "UZS", getSetting(server.Client(appId)))
+	assert.Equal(t, "UZS", getSetting(server.Client(appId)))
+	assert.Len(t, server.Requests(), 1)
+
+	// a write through another client invalidates the table
+	_, _, err := server.Client(appId).UpdateObject(&ucodesdk.Argument{TableSlug: "settings", Request: ucodesdk.Request{Data: map[string]interface{}{"guid": guids[0], "value": "USD"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", getSetting(server.Client(appId)))
+	assert.Len(t, server.Requests(), 3)
+
+	// the cache of the config is read on every call
+	ucodeApi := server.Client(appId)
+	cache := ucodesdk.NewMemoryCache(0)
+	ucodeApi.Config().Cache = cache
+	assert.Equal(t, "USD", getSetting(ucodeApi))
+	assert.Equal(t, 1, cache.Len())
+	assert.Len(t, server.Requests(), 4)
+}
+
+func TestMemoryCache(t *testing.T) {
+	cache := ucodesdk.NewMemoryCache(2)
+
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Minute)
+	_, _ = cache.Get("a")
+	cache.Set("c", []byte("3"), time.Minute)
+
+	// b was the least recently used
+	_, ok := cache.Get("b")
+	assert.False(t, ok)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(value))
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Set("d", []byte("4"), -time.Second)
+	_, ok = cache.Get("d")
+	assert.False(t, ok)
+}
@@ -0,0 +1,241 @@
+/*
+ucode-dev serves a function locally and restarts it whenever its sources change.
+
+	ucode-dev serve [-pkg ./template/cmd] [-addr :8080] [-fake-backend] [-seed seed.json]
+
+The function's runner package (cmd/main.go calling runner.Main) is built into a
+temporary binary and started with its "serve" subcommand, so invocations can
+be sent to http://localhost:8080 from Postman or the frontend. When the
+function exits, it is restarted, unless it exits right after starting, e.g.
+because the port is in use; then it is started again on the next change.
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type options struct {
+	pkg         string
+	watch       string
+	interval    time.Duration
+	addr        string
+	baseURL     string
+	fakeBackend bool
+	seed        string
+	seedAppId   string
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "serve" {
+		fmt.Fprintln(os.Stderr, "Usage: ucode-dev serve [flags]")
+		os.Exit(2)
+	}
+
+	var (
+		opts  options
+		flags = flag.NewFlagSet("ucode-dev serve", flag.ExitOnError)
+	)
+
+	flags.StringVar(&opts.pkg, "pkg", "./template/cmd", "main package of the function")
+	flags.StringVar(&opts.watch, "watch", ".", "directory watched for changes")
+	flags.DurationVar(&opts.interval, "interval", 500*time.Millisecond, "how often sources are checked for changes")
+	flags.StringVar(&opts.addr, "addr", ":8080", "address to serve the function on")
+	flags.StringVar(&opts.baseURL, "base-url", "", "uCode base url used by the function")
+	flags.BoolVar(&opts.fakeBackend, "fake-backend", false, "point the function at an in-memory uCode backend")
+	flags.StringVar(&opts.seed, "seed", "", "JSON file with objects for the fake backend, keyed by table slug")
+	flags.StringVar(&opts.seedAppId, "seed-app-id", "", "app id the seed objects belong to")
+	_ = flags.Parse(os.Args[2:])
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, opts, log.New(os.Stderr, "ucode-dev: ", log.LstdFlags)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, opts options, logger *log.Logger) error {
+	tmpDir, err := os.MkdirTemp("", "ucode-dev")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var (
+		binary   = filepath.Join(tmpDir, "function")
+		process  *child
+		lastSeen time.Time
+		ticker   = time.NewTicker(opts.interval)
+	)
+	defer ticker.Stop()
+	defer func() { process.stop() }()
+
+	for {
+		changed, err := latestChange(opts.watch)
+		if err != nil {
+			return err
+		}
+
+		if changed.After(lastSeen) {
+			lastSeen = changed
+
+			logger.Printf("building %s", opts.pkg)
+			if err = build(ctx, opts.pkg, binary); err != nil {
+				// keep the previous version running until the sources compile again
+				logger.Printf("build failed: %v", err)
+			} else {
+				process.stop()
+				if process, err = startChild(binary, serveArgs(opts)); err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-process.exited():
+			logger.Printf("function exited: %v", process.err)
+			if time.Since(process.started) < minUptime {
+				// it fails on start, e.g. the port is in use, restarting would fail again
+				logger.Printf("waiting for a source change to restart it")
+				process = nil
+				continue
+			}
+
+			logger.Printf("restarting the function")
+			if process, err = startChild(binary, serveArgs(opts)); err != nil {
+				return err
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// minUptime is how long the function has to run before it is restarted when it exits.
+const minUptime = 2 * time.Second
+
+// child is a running function process.
+type child struct {
+	cmd     *exec.Cmd
+	started time.Time
+	// done is closed when the process exits, err is its exit error.
+	done chan struct{}
+	err  error
+}
+
+func startChild(binary string, args []string) (*child, error) {
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	c := &child{cmd: cmd, started: time.Now(), done: make(chan struct{})}
+	go func() {
+		c.err = c.cmd.Wait()
+		close(c.done)
+	}()
+
+	return c, nil
+}
+
+// exited returns a channel closed when the process exits, nil (blocking forever) without a process.
+func (c *child) exited() <-chan struct{} {
+	if c == nil {
+		return nil
+	}
+
+	return c.done
+}
+
+// stop interrupts the process and kills it when it does not exit within 5 seconds.
+func (c *child) stop() {
+	if c == nil {
+		return
+	}
+
+	_ = c.cmd.Process.Signal(os.Interrupt)
+
+	select {
+	case <-c.done:
+	case <-time.After(5 * time.Second):
+		_ = c.cmd.Process.Kill()
+		<-c.done
+	}
+}
+
+func build(ctx context.Context, pkg, output string) error {
+	cmd := exec.CommandContext(ctx, "go", "build", "-o", output, pkg)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+func serveArgs(opts options) []string {
+	args := []string{"serve", "-addr", opts.addr}
+
+	if opts.baseURL != "" {
+		args = append(args, "-base-url", opts.baseURL)
+	}
+	if opts.fakeBackend {
+		args = append(args, "-fake-backend")
+	}
+	if opts.seed != "" {
+		args = append(args, "-seed", opts.seed, "-seed-app-id", opts.seedAppId)
+	}
+
+	return args
+}
+
+// latestChange returns the latest modification time of the function sources under dir.
+func latestChange(dir string) (time.Time, error) {
+	var latest time.Time
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch filepath.Ext(name) {
+		case ".go", ".json", ".jsonl", ".env", ".mod", ".sum":
+		default:
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+
+		return nil
+	})
+
+	return latest, err
+}
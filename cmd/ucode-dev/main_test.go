@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChild(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+
+	crashed, err := startChild(sh, []string{"-c", "exit 3"})
+	if assert.NoError(t, err) {
+		select {
+		case <-crashed.exited():
+			var exitErr *exec.ExitError
+			if assert.ErrorAs(t, crashed.err, &exitErr) {
+				assert.Equal(t, 3, exitErr.ExitCode())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("exit was not noticed")
+		}
+	}
+
+	running, err := startChild(sh, []string{"-c", "exec sleep 30"})
+	if assert.NoError(t, err) {
+		start := time.Now()
+		running.stop()
+		assert.Less(t, time.Since(start), 4*time.Second)
+	}
+
+	var none *child
+	assert.Nil(t, none.exited())
+	none.stop()
+}
+
+func TestLatestChange(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+
+	for _, name := range []string{"handler.go", "README.md", filepath.Join(".git", "HEAD.go")} {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, nil, 0o644))
+		assert.NoError(t, os.Chtimes(path, old, old))
+	}
+
+	latest, err := latestChange(dir)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, old, latest, time.Second)
+
+	// hidden directories and other files are not watched
+	now := time.Now()
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, "README.md"), now, now))
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, ".git", "HEAD.go"), now, now))
+	latest, err = latestChange(dir)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, old, latest, time.Second)
+
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, "handler.go"), now, now))
+	latest, err = latestChange(dir)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, now, latest, time.Second)
+}
+
+func TestServeArgs(t *testing.T) {
+	assert.Equal(t, []string{"serve", "-addr", ":8080", "-fake-backend", "-seed", "seed.json", "-seed-app-id", "app"},
+		serveArgs(options{addr: ":8080", fakeBackend: true, seed: "seed.json", seedAppId: "app"}))
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golanguzb70/ucode-sdk/backup"
+)
+
+func runBackup(args []string) error {
+	var (
+		client      clientFlags
+		opts        backup.BackupOptions
+		tables, out string
+		links       stringsFlag
+		flags       = flag.NewFlagSet("ucode backup", flag.ExitOnError)
+	)
+
+	client.register(flags)
+	flags.StringVar(&tables, "tables", "", "comma separated table slugs, referenced tables first")
+	flags.Var(&links, "link", "many-to-many relation as table_from:table_to, repeatable")
+	flags.IntVar(&opts.PageSize, "page-size", 500, "objects read per request")
+	flags.StringVar(&out, "o", "", "archive file to write")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ucode backup -tables <slug,...> -o <archive.zip> [flags]\n\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if tables == "" || out == "" {
+		flags.Usage()
+		return errors.New("tables and o are required")
+	}
+
+	opts.Tables = strings.Split(tables, ",")
+	for _, value := range links {
+		link, err := backup.ParseLink(value)
+		if err != nil {
+			return err
+		}
+		opts.Links = append(opts.Links, link)
+	}
+
+	file, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	manifest, err := backup.Backup(context.Background(), client.client(), file, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range manifest.Tables {
+		fmt.Fprintf(os.Stderr, "%s: %d objects\n", table.TableSlug, table.Count)
+	}
+	for _, link := range manifest.Links {
+		fmt.Fprintf(os.Stderr, "%s: %d links\n", link.Link, link.Count)
+	}
+
+	return file.Close()
+}
+
+func runRestore(args []string) error {
+	var (
+		client clientFlags
+		opts   backup.RestoreOptions
+		flags  = flag.NewFlagSet("ucode restore", flag.ExitOnError)
+	)
+
+	client.register(flags)
+	flags.IntVar(&opts.BatchSize, "batch-size", 100, "objects sent per request")
+	flags.BoolVar(&opts.NewGuids, "new-guids", false, "give restored objects new guids and rewrite references to them")
+	flags.StringVar(&opts.Checkpoint, "checkpoint", "", "progress file, default <archive>.checkpoint")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ucode restore [flags] <archive.zip>\n\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected one archive")
+	}
+
+	path := flags.Arg(0)
+	if opts.Checkpoint == "" {
+		opts.Checkpoint = path + ".checkpoint"
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	report, err := backup.Restore(context.Background(), client.client(), file, info.Size(), opts)
+	for table, count := range report.Tables {
+		fmt.Fprintf(os.Stderr, "%s: %d objects\n", table, count)
+	}
+	fmt.Fprintf(os.Stderr, "links: %d\n", report.Links)
+	if err != nil {
+		return fmt.Errorf("%w (run again to continue from %s)", err, opts.Checkpoint)
+	}
+
+	return nil
+}
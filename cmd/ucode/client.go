@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+)
+
+// clientFlags are the flags of the commands talking to uCode.
+type clientFlags struct {
+	baseURL string
+	appId   string
+	timeout time.Duration
+}
+
+func (c *clientFlags) register(flags *flag.FlagSet) {
+	baseURL := os.Getenv("UCODE_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://api.admin.u-code.io"
+	}
+
+	flags.StringVar(&c.baseURL, "base-url", baseURL, "uCode base url ($UCODE_BASE_URL)")
+	flags.StringVar(&c.appId, "app-id", os.Getenv("UCODE_APP_ID"), "app id ($UCODE_APP_ID)")
+	flags.DurationVar(&c.timeout, "timeout", time.Minute, "timeout of a single request")
+}
+
+func (c *clientFlags) client() ucodesdk.UcodeApis {
+	return ucodesdk.New(&ucodesdk.Config{
+		BaseURL:        c.baseURL,
+		AppId:          c.appId,
+		RequestTimeout: c.timeout,
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golanguzb70/ucode-sdk/exporter"
+)
+
+func runExport(args []string) error {
+	var (
+		client               clientFlags
+		opts                 exporter.Options
+		filter, columns, out string
+		flags                = flag.NewFlagSet("ucode export", flag.ExitOnError)
+	)
+
+	client.register(flags)
+	flags.StringVar(&opts.TableSlug, "table", "", "table slug to export")
+	flags.StringVar(&filter, "filter", "", `JSON filter, e.g. {"price": 15000}`)
+	flags.StringVar(&columns, "columns", "", "comma separated columns to write in order, dotted for relations (default all)")
+	flags.StringVar(&opts.Format, "format", "", "csv, ndjson or xlsx (default from the output extension, csv for stdout)")
+	flags.IntVar(&opts.PageSize, "page-size", 500, "objects read per request")
+	flags.StringVar(&out, "o", "", "output file (default stdout)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ucode export -table <slug> [flags]\n\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if opts.TableSlug == "" {
+		flags.Usage()
+		return errors.New("table is required")
+	}
+
+	if filter != "" {
+		if err := json.Unmarshal([]byte(filter), &opts.Filter); err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+	if columns != "" {
+		opts.Columns = strings.Split(columns, ",")
+	}
+	if opts.Format == "" && out != "" {
+		switch ext := strings.TrimPrefix(filepath.Ext(out), "."); ext {
+		case exporter.FormatNDJSON, exporter.FormatXLSX:
+			opts.Format = ext
+		case "jsonl":
+			opts.Format = exporter.FormatNDJSON
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	written, err := exporter.Export(context.Background(), client.client(), w, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d objects\n", written)
+	return nil
+}
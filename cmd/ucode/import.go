@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/importer"
+)
+
+func runImport(args []string) error {
+	var (
+		client                   clientFlags
+		opts                     importer.Options
+		bulk                     ucodesdk.BulkOptions
+		mappingPath, rejectsPath string
+		comma                    string
+		flags                    = flag.NewFlagSet("ucode import", flag.ExitOnError)
+	)
+
+	client.register(flags)
+	flags.StringVar(&opts.TableSlug, "table", "", "table slug to import into")
+	flags.StringVar(&mappingPath, "mapping", "", "JSON file mapping CSV columns to field slugs")
+	flags.StringVar(&rejectsPath, "rejects", "", "CSV file the rejected rows are written to (default <file>.rejects.csv)")
+	flags.StringVar(&comma, "comma", ",", "field delimiter")
+	flags.IntVar(&opts.BatchSize, "batch", 1000, "rows read before they are written")
+	flags.IntVar(&bulk.ChunkSize, "chunk", 100, "objects sent in one request")
+	flags.IntVar(&bulk.Concurrency, "concurrency", 4, "requests sent at once")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "validate the rows without writing them")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ucode import -table <slug> -mapping <mapping.json> [flags] <file.csv>\n\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 || opts.TableSlug == "" || mappingPath == "" || comma == "" {
+		flags.Usage()
+		return errors.New("table, mapping, comma and exactly one file are required")
+	}
+	path := flags.Arg(0)
+
+	mapping, err := importer.LoadMapping(mappingPath)
+	if err != nil {
+		return err
+	}
+	opts.Mapping = mapping
+	opts.Bulk = &bulk
+	opts.Comma = []rune(comma)[0]
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if rejectsPath == "" {
+		rejectsPath = path + ".rejects.csv"
+	}
+	rejects, err := os.Create(rejectsPath)
+	if err != nil {
+		return err
+	}
+	defer rejects.Close()
+	opts.Rejects = rejects
+
+	report, err := importer.Import(context.Background(), client.client(), file, opts)
+	fmt.Printf("rows: %d, created: %d, updated: %d, rejected: %d\n", report.Rows, report.Created, report.Updated, report.Rejected)
+	if err != nil {
+		return err
+	}
+
+	if report.Rejected > 0 {
+		fmt.Printf("rejected rows are written to %s\n", rejectsPath)
+		return nil
+	}
+
+	rejects.Close()
+	return os.Remove(rejectsPath)
+}
@@ -0,0 +1,76 @@
+/*
+ucode is the command line tool for uCode function projects and app data.
+
+	ucode new <name> [flags]	generate a new function project
+	ucode import [flags] <file.csv>	import a CSV file into a table
+	ucode export [flags]		export a table to CSV, JSON Lines or XLSX
+	ucode backup [flags]		save the tables of an app to an archive
+	ucode restore [flags] <archive.zip>	restore an archive into an app
+	ucode migrate [flags]		copy tables to another app
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command runs a subcommand with the arguments following its name.
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"new":     {usage: "generate a new function project", run: runNew},
+	"import":  {usage: "import a CSV file into a table", run: runImport},
+	"export":  {usage: "export a table to CSV, JSON Lines or XLSX", run: runExport},
+	"backup":  {usage: "save the tables of an app to an archive", run: runBackup},
+	"restore": {usage: "restore an archive into an app", run: runRestore},
+	"migrate": {usage: "copy tables to another app", run: runMigrate},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "ucode: unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "ucode:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "Usage: ucode <command> [flags]\n\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+}
+
+// stringsFlag collects a repeated string flag.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return fmt.Sprint(*f)
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/migrate"
+)
+
+func runMigrate(args []string) error {
+	var (
+		client            clientFlags
+		opts              migrate.Options
+		tables, toBaseURL string
+		verifyOnly        bool
+		flags             = flag.NewFlagSet("ucode migrate", flag.ExitOnError)
+	)
+
+	client.register(flags)
+	flags.StringVar(&opts.TargetAppId, "to-app-id", "", "app id to copy to")
+	flags.StringVar(&toBaseURL, "to-base-url", "", "uCode base url of the target app, -base-url by default")
+	flags.StringVar(&tables, "tables", "", "comma separated table slugs, referenced tables first")
+	flags.IntVar(&opts.BatchSize, "batch-size", 100, "objects read and written at once")
+	flags.StringVar(&opts.Conflict, "conflict", migrate.ConflictSkip, "objects existing in the target: skip, overwrite or fail")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "report what would be written without writing")
+	flags.BoolVar(&opts.DisableFaas, "disable-faas", true, "do not run the functions of the target tables")
+	flags.BoolVar(&opts.Verify, "verify", true, "compare row counts and checksums after copying")
+	flags.BoolVar(&verifyOnly, "verify-only", false, "only compare the tables")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ucode migrate -app-id <from> -to-app-id <to> -tables <slug,...> [flags]\n\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if tables == "" || opts.TargetAppId == "" {
+		flags.Usage()
+		return errors.New("tables and to-app-id are required")
+	}
+	opts.Tables = strings.Split(tables, ",")
+
+	source := client.client()
+	target := source
+	if toBaseURL != "" {
+		target = ucodesdk.New(&ucodesdk.Config{BaseURL: toBaseURL, AppId: opts.TargetAppId, RequestTimeout: client.timeout})
+	}
+
+	var (
+		ctx    = context.Background()
+		report migrate.Report
+		err    error
+	)
+	if verifyOnly {
+		var verifications []migrate.Verification
+		verifications, err = migrate.Verify(ctx, source, target, opts)
+		for i := range verifications {
+			report.Tables = append(report.Tables, migrate.TableReport{TableSlug: verifications[i].TableSlug, Verification: &verifications[i]})
+		}
+	} else {
+		report, err = migrate.Migrate(ctx, source, target, opts)
+	}
+
+	for _, table := range report.Tables {
+		if !verifyOnly {
+			fmt.Fprintf(os.Stderr, "%s: read %d, created %d, updated %d, skipped %d\n", table.TableSlug, table.Read, table.Created, table.Updated, table.Skipped)
+		}
+
+		if v := table.Verification; v != nil {
+			status := "ok"
+			if !v.OK() {
+				status = "MISMATCH"
+			}
+			fmt.Fprintf(os.Stderr, "%s: %s, source %d, target %d, missing %d, different %d\n", table.TableSlug, status, v.SourceCount, v.TargetCount, v.Missing, v.Different)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if (opts.Verify || verifyOnly) && !opts.DryRun && !report.Verified() {
+		return errors.New("verification failed")
+	}
+
+	return nil
+}
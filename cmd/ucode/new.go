@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/golanguzb70/ucode-sdk/scaffold"
+)
+
+func runNew(args []string) error {
+	var (
+		opts     scaffold.Options
+		triggers stringsFlag
+		flags    = flag.NewFlagSet("ucode new", flag.ExitOnError)
+	)
+
+	flags.StringVar(&opts.Module, "module", "", "go module path of the project (default <name>)")
+	flags.StringVar(&opts.Dir, "dir", "", "directory the project is written to (default <name>)")
+	flags.Var(&triggers, "trigger", "table_slug:BEFORE|AFTER:METHOD[,METHOD] or HTTP, may be repeated")
+	flags.BoolVar(&opts.Force, "force", false, "overwrite existing files")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ucode new <name> [flags]\n\nMethods: %s\n\n", strings.Join(scaffold.Methods, ", "))
+		flags.PrintDefaults()
+	}
+
+	// the name comes first, flags follow it
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		flags.Usage()
+		return errors.New("function name is required")
+	}
+	opts.Name = args[0]
+	_ = flags.Parse(args[1:])
+
+	for _, value := range triggers {
+		trigger, err := scaffold.ParseTrigger(value)
+		if err != nil {
+			return err
+		}
+		opts.Triggers = append(opts.Triggers, trigger)
+	}
+
+	written, err := scaffold.Generate(opts)
+	for _, path := range written {
+		fmt.Println("created", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = opts.Name
+	}
+	fmt.Printf("\nNext steps:\n  cd %s\n  go get github.com/golanguzb70/ucode-sdk@latest && go mod tidy\n  go test ./... && go run ./cmd\n", dir)
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// ReadStats counts how the reads of a client were answered.
+	ReadStats struct {
+		// Sent is the number of read requests sent to the server.
+		Sent int64
+		// Coalesced is the number of reads which got the response of an identical read already in flight.
+		Coalesced int64
+		// CacheHits is the number of reads answered from Config.Cache.
+		CacheHits int64
+	}
+
+	readCounters struct {
+		sent, cacheHits atomic.Int64
+	}
+
+	// flightGroup shares the response of a request with identical requests sent while it is in flight.
+	flightGroup struct {
+		mu      sync.Mutex
+		flights map[string]*flight
+		// shared counts the calls which joined a call in flight
+		shared atomic.Int64
+	}
+
+	flight struct {
+		done       chan struct{}
+		statusCode int
+		body       []byte
+		err        error
+		// waiters is the number of callers waiting, the request is canceled when the last one stops
+		waiters int
+		// deadline is the earliest deadline of the callers, timer cancels the request at it
+		deadline time.Time
+		timer    *time.Timer
+		cancel   context.CancelCauseFunc
+	}
+)
+
+func (o *object) Info() InfoApis {
+	return o
+}
+
+func (o *object) ReadStats() ReadStats {
+	return ReadStats{
+		Sent:      o.readCounters.sent.Load(),
+		Coalesced: o.flights.shared.Load(),
+		CacheHits: o.readCounters.cacheHits.Load(),
+	}
+}
+
+/*
+do calls fn unless a call with the same key is in flight, then it waits for that call and returns its result.
+fn runs in its own goroutine, so every caller stops waiting when its ctx is done while the others keep waiting.
+The ctx of fn has the earliest deadline of the callers and is canceled when the last caller stops waiting.
+*/
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (int, []byte, error)) (int, []byte, error) {
+	g.mu.Lock()
+	current, ok := g.flights[key]
+	if ok {
+		g.shared.Add(1)
+	} else {
+		if g.flights == nil {
+			g.flights = map[string]*flight{}
+		}
+
+		shared, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+		current = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = current
+		go g.run(shared, key, current, fn)
+	}
+
+	current.waiters++
+	if deadline, ok := ctx.Deadline(); ok && (current.deadline.IsZero() || deadline.Before(current.deadline)) {
+		current.deadline = deadline
+		if current.timer == nil {
+			cancel := current.cancel
+			current.timer = time.AfterFunc(time.Until(deadline), func() { cancel(context.DeadlineExceeded) })
+		} else {
+			current.timer.Reset(time.Until(deadline))
+		}
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-current.done:
+		return current.statusCode, current.body, current.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		current.waiters--
+		if current.waiters == 0 {
+			// nobody waits for the response anymore, later reads send a new request
+			current.cancel(ctx.Err())
+			if g.flights[key] == current {
+				delete(g.flights, key)
+			}
+		}
+		g.mu.Unlock()
+
+		return 0, nil, ctx.Err()
+	}
+}
+
+// run calls fn and releases the callers waiting for it, also when fn panics.
+func (g *flightGroup) run(ctx context.Context, key string, current *flight, fn func(ctx context.Context) (int, []byte, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			current.err = fmt.Errorf("read panicked: %v", r)
+		}
+		if current.err != nil && errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+			current.err = fmt.Errorf("%w: %v", context.DeadlineExceeded, current.err)
+		}
+
+		g.mu.Lock()
+		if g.flights[key] == current {
+			delete(g.flights, key)
+		}
+		if current.timer != nil {
+			current.timer.Stop()
+		}
+		g.mu.Unlock()
+		current.cancel(nil)
+		close(current.done)
+	}()
+
+	current.statusCode, current.body, current.err = fn(ctx)
+}
+
+/*
+fetch sends a read request of the table. Unless coalesce is false, identical reads
+(same app, url, method and body) sent at the same time share one request. The shared
+request has the earliest deadline of the callers and is canceled once every caller's
+context is done, each caller stops waiting when its own context is done. Reads
+started after a write to the table do not join a request sent before it.
+*/
+func (o *object) fetch(ctx context.Context, appId, tableSlug, url, method string, body interface{}, headers map[string]string, coalesce bool) (int, []byte, error) {
+	if !coalesce {
+		o.readCounters.sent.Add(1)
+		return o.send(ctx, url, method, body, headers)
+	}
+
+	if appId == "" {
+		appId = o.config.AppId
+	}
+
+	bodyByte, err := json.Marshal(body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	key := fmt.Sprintf("%s\x00%s\x00%d\x00%s %s\x00%s", appId, tableSlug, tableGenerations.get(appId, tableSlug), method, url, bodyByte)
+
+	return o.flights.do(ctx, key, func(shared context.Context) (int, []byte, error) {
+		o.readCounters.sent.Add(1)
+		return o.send(shared, url, method, body, headers)
+	})
+}
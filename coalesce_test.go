@@ -0,0 +1,169 @@
+package ucodesdk_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalescing(t *testing.T) {
+	var (
+		hits    atomic.Int64
+		release = make(chan struct{})
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"status": "OK", "data": {"data": {"response": {"guid": "1", "name": "room"}}}}`))
+	}))
+	defer server.Close()
+
+	const readers = 10
+
+	// readAll starts the reads, releases the server once waitFor reports true and returns the room names
+	readAll := func(t *testing.T, ucodeApi ucodesdk.UcodeApis, disable bool, waitFor func() bool) []interface{} {
+		var (
+			wg    sync.WaitGroup
+			names = make([]interface{}, readers)
+		)
+		for i := 0; i < readers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				room, _, err := ucodeApi.GetSingleSlim(&ucodesdk.Argument{
+					TableSlug:         "room",
+					Request:           ucodesdk.Request{Data: map[string]interface{}{"guid": "1"}},
+					DisableCoalescing: disable,
+				})
+				assert.NoError(t, err)
+				names[i] = room.Data.Data.Response["name"]
+			}(i)
+		}
+
+		assert.Eventually(t, waitFor, time.Second, time.Millisecond)
+		release <- struct{}{}
+		if disable {
+			for i := 1; i < readers; i++ {
+				release <- struct{}{}
+			}
+		}
+		wg.Wait()
+
+		return names
+	}
+
+	t.Run("coalesced", func(t *testing.T) {
+		ucodeApi := ucodesdk.New(&ucodesdk.Config{BaseURL: server.URL, AppId: "test_app_id"})
+		hits.Store(0)
+
+		names := readAll(t, ucodeApi, false, func() bool { return ucodeApi.Info().ReadStats().Coalesced == readers-1 })
+		for _, name := range names {
+			assert.Equal(t, "room", name)
+		}
+		assert.EqualValues(t, 1, hits.Load())
+		assert.Equal(t, ucodesdk.ReadStats{Sent: 1, Coalesced: readers - 1}, ucodeApi.Info().ReadStats())
+	})
+
+	t.Run("canceled caller", func(t *testing.T) {
+		var (
+			ucodeApi    = ucodesdk.New(&ucodesdk.Config{BaseURL: server.URL, AppId: "test_app_id"})
+			ctx, cancel = context.WithCancel(context.Background())
+			canceled    = make(chan error)
+			wg          sync.WaitGroup
+		)
+		hits.Store(0)
+
+		// the first read is canceled while the second one waits for the same request
+		go func() {
+			_, err := ucodeApi.V2().GetSingleSlim(ctx, "room", "1")
+			canceled <- err
+		}()
+		assert.Eventually(t, func() bool { return hits.Load() == 1 }, time.Second, time.Millisecond)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			room, err := ucodeApi.V2().GetSingleSlim(context.Background(), "room", "1")
+			assert.NoError(t, err)
+			assert.Equal(t, "room", room.String("name"))
+		}()
+		assert.Eventually(t, func() bool { return ucodeApi.Info().ReadStats().Coalesced == 1 }, time.Second, time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-canceled, context.Canceled)
+
+		release <- struct{}{}
+		wg.Wait()
+		assert.EqualValues(t, 1, hits.Load())
+	})
+
+	t.Run("opt out", func(t *testing.T) {
+		ucodeApi := ucodesdk.New(&ucodesdk.Config{BaseURL: server.URL, AppId: "test_app_id"})
+		hits.Store(0)
+
+		readAll(t, ucodeApi, true, func() bool { return hits.Load() == readers })
+		assert.Equal(t, ucodesdk.ReadStats{Sent: readers}, ucodeApi.Info().ReadStats())
+	})
+}
+
+func TestCoalescingAbort(t *testing.T) {
+	var (
+		started = make(chan struct{}, 1)
+		aborted = make(chan struct{}, 1)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the server notices a closed connection only once the body is read
+		_, _ = io.Copy(io.Discard, r.Body)
+		started <- struct{}{}
+		select {
+		case <-r.Context().Done():
+			aborted <- struct{}{}
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	t.Run("canceled", func(t *testing.T) {
+		ucodeApi := ucodesdk.New(&ucodesdk.Config{BaseURL: server.URL, AppId: "test_app_id"})
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error)
+		go func() {
+			_, err := ucodeApi.V2().GetSingleSlim(ctx, "room", "1")
+			errs <- err
+		}()
+
+		<-started
+		cancel()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+
+		select {
+		case <-aborted:
+		case <-time.After(time.Second):
+			t.Fatal("the request was not aborted after its only caller was canceled")
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ucodeApi := ucodesdk.New(&ucodesdk.Config{BaseURL: server.URL, AppId: "test_app_id"})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := ucodeApi.V2().GetSingleSlim(ctx, "room", "1")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		<-started
+
+		select {
+		case <-aborted:
+		case <-time.After(time.Second):
+			t.Fatal("the request was not aborted at the deadline of its only caller")
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package ucodesdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+)
+
+const gzipEncoding = "gzip"
+
+// compress gzips data when Config.CompressMinSize is set and data is at least that big.
+func (o *object) compress(data []byte) ([]byte, bool, error) {
+	if o.config.CompressMinSize <= 0 || len(data) < o.config.CompressMinSize {
+		return data, false, nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, false, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, false, err
+	}
+
+	return buf.Bytes(), true, nil
+}
+
+// decompress replaces a gzip encoded response body with the decoded one.
+func decompress(resp *http.Response) error {
+	if resp.Header.Get("Content-Encoding") != gzipEncoding {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	switch {
+	case errors.Is(err, io.EOF):
+		// an empty body is not a gzip stream
+		return nil
+	case err != nil:
+		resp.Body.Close()
+		return err
+	}
+
+	resp.Body = &gzipBody{Reader: reader, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return nil
+}
+
+// gzipBody closes the gzip reader and the response body under it.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (g *gzipBody) Close() error {
+	g.Reader.Close()
+	return g.body.Close()
+}
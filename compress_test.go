@@ -0,0 +1,70 @@
+package ucodesdk_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompression(t *testing.T) {
+	var (
+		contentEncoding, acceptEncoding string
+		requestBody                     map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentEncoding, acceptEncoding = r.Header.Get("Content-Encoding"), r.Header.Get("Accept-Encoding")
+
+		var body io.Reader = r.Body
+		if contentEncoding == "gzip" {
+			reader, err := gzip.NewReader(r.Body)
+			if !assert.NoError(t, err) {
+				return
+			}
+			body = reader
+		}
+		requestBody = nil
+		assert.NoError(t, json.NewDecoder(body).Decode(&requestBody))
+
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		defer writer.Close()
+		_, _ = writer.Write([]byte(`{"status": "OK", "data": {"data": {"response": [{"guid": "1", "name": "house"}]}}}`))
+	}))
+	defer server.Close()
+
+	ucodeApi := ucodesdk.New(&ucodesdk.Config{BaseURL: server.URL, AppId: "test_app_id", CompressMinSize: 1024})
+
+	t.Run("small request", func(t *testing.T) {
+		list, _, err := ucodeApi.GetList(&ucodesdk.ArgumentWithPegination{
+			TableSlug: "houses",
+			Request:   ucodesdk.Request{Data: map[string]interface{}{"name": "house"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "", contentEncoding)
+		assert.Equal(t, "gzip", acceptEncoding)
+		assert.Equal(t, "house", requestBody["data"].(map[string]interface{})["name"])
+		assert.Equal(t, "house", list.Data.Data.Response[0]["name"])
+	})
+
+	t.Run("large request", func(t *testing.T) {
+		objects := make([]map[string]interface{}, 100)
+		for i := range objects {
+			objects[i] = map[string]interface{}{"guid": fmt.Sprint(i), "name": fmt.Sprintf("house_%d", i)}
+		}
+
+		_, _, err := ucodeApi.MultipleUpdate(&ucodesdk.Argument{
+			TableSlug: "houses",
+			Request:   ucodesdk.Request{Data: map[string]interface{}{"objects": objects}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "gzip", contentEncoding)
+		assert.Len(t, requestBody["data"].(map[string]interface{})["objects"], 100)
+	})
+}
@@ -4,13 +4,46 @@ import (
 	"time"
 )
 
+const DefaultFunctionsURL = "https://ofs.u-code.io"
+
 type Config struct {
-	AppId          string
-	BaseURL        string
+	AppId   string
+	BaseURL string
+	// FunctionsURL is the gateway other functions are invoked through, DefaultFunctionsURL when empty.
+	FunctionsURL string
+	// FunctionName is the name of the running function, it is sent as the caller of invoked functions.
 	FunctionName   string
 	RequestTimeout time.Duration
+	// MaxResponseSize is the limit of response bodies in bytes, bigger responses fail with ErrResponseTooLarge. No limit when 0.
+	MaxResponseSize int64
+	// CompressMinSize turns on gzip for request bodies of at least this many bytes. Off when 0.
+	CompressMinSize int
+	// Retry retries failed create and update requests, they are sent once when it is nil.
+	Retry *RetryPolicy
+	// Cache stores the responses of reads with Request.IsCached set, an in-memory cache of 1000 responses shared by all clients when nil.
+	Cache Cache
+	// CacheTTL is how long a cached response is used, 1 minute by default.
+	CacheTTL time.Duration
+	/*
+		Backend is the database of AppId, set it for Postgres apps. When empty, a probe request
+		is sent the first time it is needed; it can only tell Mongo apps, any other app is unknown
+		and its methods are sent like they are to Mongo apps.
+	*/
+	Backend Backend
+	// Backends are the databases of other app ids, keyed by app id, probed when missing like Backend.
+	Backends map[string]Backend
+	// BackendRetry is how long an unknown database or a failed probe is kept before the next probe, 1 minute by default.
+	BackendRetry time.Duration
 }
 
 func (cfg *Config) SetBaseUrl(url string) {
 	cfg.BaseURL = url
-}
\ No newline at end of file
+}
+
+func (cfg *Config) functionsURL() string {
+	if cfg.FunctionsURL != "" {
+		return cfg.FunctionsURL
+	}
+
+	return DefaultFunctionsURL
+}
@@ -0,0 +1,189 @@
+package ucodesdk
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+/*
+WithRelations expands relations of GetSingleSlim and GetListSlim into <relation>_id_data,
+read it with Object.Object or Object.Decode.
+
+A dotted path expands the relations of a related object, "room.building" sets
+building_id_data in room_id_data. The first level is expanded by the server, every
+deeper level is loaded with one GetListSlim call per relation.
+*/
+func WithRelations(relations ...string) Option {
+	return func(opts *callOptions) { opts.relations = append(opts.relations, relations...) }
+}
+
+// WithRelationFields expands the relation like WithRelations and keeps only the fields (and guid) of the related objects.
+func WithRelationFields(relation string, fields ...string) Option {
+	return func(opts *callOptions) {
+		if opts.relationFields == nil {
+			opts.relationFields = map[string][]string{}
+		}
+		opts.relations = append(opts.relations, relation)
+		opts.relationFields[relation] = append(opts.relationFields[relation], fields...)
+	}
+}
+
+// relationPaths returns the selected relation paths and their parents, parents first.
+func (opts callOptions) relationPaths() []string {
+	var (
+		paths []string
+		seen  = map[string]bool{}
+	)
+	for _, relation := range opts.relations {
+		segments := strings.Split(relation, ".")
+		for i := range segments {
+			path := strings.Join(segments[:i+1], ".")
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		return strings.Count(paths[i], ".") < strings.Count(paths[j], ".")
+	})
+
+	return paths
+}
+
+// relationData adds the keys expanding the first level of relations to the request data.
+func (opts callOptions) relationData(data map[string]interface{}) map[string]interface{} {
+	var selected []string
+	for _, path := range opts.relationPaths() {
+		if !strings.Contains(path, ".") {
+			selected = append(selected, path)
+		}
+	}
+	if len(selected) == 0 {
+		return data
+	}
+
+	// the filter of the caller is not modified
+	withRelations := make(map[string]interface{}, len(data)+2)
+	for key, value := range data {
+		withRelations[key] = value
+	}
+	withRelations["with_relations"] = true
+	withRelations["selected_relations"] = selected
+
+	return withRelations
+}
+
+// expandRelations loads the nested relations of the objects and drops the fields not selected by WithRelationFields.
+func (v *v2) expandRelations(ctx context.Context, objects []Object, options callOptions) error {
+	paths := options.relationPaths()
+
+	for _, path := range paths {
+		dot := strings.LastIndex(path, ".")
+		if dot < 0 {
+			continue
+		}
+
+		var (
+			relation = path[dot+1:]
+			parents  = relatedObjects(objects, path[:dot])
+			guids    []string
+			seen     = map[string]bool{}
+		)
+		for _, parent := range parents {
+			guid := cast.ToString(parent[relation+"_id"])
+			if guid != "" && !seen[guid] {
+				seen[guid] = true
+				guids = append(guids, guid)
+			}
+		}
+
+		related, err := v.loadRelated(ctx, relation, guids, options)
+		if err != nil {
+			return err
+		}
+
+		for _, parent := range parents {
+			if object, ok := related[cast.ToString(parent[relation+"_id"])]; ok {
+				parent[relation+"_id_data"] = map[string]interface{}(object)
+			}
+		}
+	}
+
+	for _, path := range paths {
+		fields, ok := options.relationFields[path]
+		if !ok {
+			continue
+		}
+
+		keep := map[string]bool{"guid": true}
+		for _, field := range fields {
+			keep[field] = true
+		}
+		// the nested relations of the path are kept with their guid
+		for _, nested := range paths {
+			if name := strings.TrimPrefix(nested, path+"."); name != nested && !strings.Contains(name, ".") {
+				keep[name+"_id"], keep[name+"_id_data"] = true, true
+			}
+		}
+
+		for _, object := range relatedObjects(objects, path) {
+			for field := range object {
+				if !keep[field] {
+					delete(object, field)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadRelated gets the objects of the table with the guids, keyed by guid.
+func (v *v2) loadRelated(ctx context.Context, tableSlug string, guids []string, options callOptions) (map[string]Object, error) {
+	related := make(map[string]Object, len(guids))
+
+	opts := []Option{WithAppId(options.appId)}
+	if options.disableFaas {
+		opts = append(opts, WithDisableFaas())
+	}
+	if options.cached {
+		opts = append(opts, WithCache())
+	}
+
+	for start := 0; start < len(guids); start += defaultLoaderMaxBatch {
+		end := start + defaultLoaderMaxBatch
+		if end > len(guids) {
+			end = len(guids)
+		}
+
+		objects, _, err := v.GetListSlim(ctx, tableSlug, map[string]interface{}{"guid": guids[start:end]}, append(opts, WithPage(1, end-start))...)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range objects {
+			related[object.GUID()] = object
+		}
+	}
+
+	return related, nil
+}
+
+// relatedObjects follows the dotted relation path from the objects and returns the related objects found.
+func relatedObjects(objects []Object, path string) []Object {
+	for _, relation := range strings.Split(path, ".") {
+		var next []Object
+		for _, object := range objects {
+			if related := object.Object(relation); related != nil {
+				next = append(next, related)
+			}
+		}
+		objects = next
+	}
+
+	return objects
+}
@@ -0,0 +1,202 @@
+/*
+Package exporter dumps uCode tables to CSV, JSON Lines and XLSX.
+
+Rows are read page by page with GetListSlim and written as soon as a page
+arrives, so only one page is held in memory. Nested objects, like expanded
+relations, are flattened to dotted columns ("room.name").
+*/
+package exporter
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+)
+
+// Formats understood by Export.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+const defaultPageSize = 500
+
+// ErrUnknownColumn is returned when Options.Columns is empty and a row has a column the first page has not.
+var ErrUnknownColumn = errors.New("column is not in the header, set Options.Columns")
+
+type Options struct {
+	AppId       string
+	TableSlug   string
+	DisableFaas bool
+	// Filter is sent as the request data of GetListSlim.
+	Filter map[string]interface{}
+	// Format is csv (default), ndjson or xlsx.
+	Format string
+	/*
+		Columns selects and orders the written columns, dotted for nested values.
+		When empty, the columns of the first page are written in name order (guid first)
+		and a later row with a column missing from them fails the export with ErrUnknownColumn,
+		for ndjson every column of every row is written.
+	*/
+	Columns []string
+	// PageSize is the limit of one GetListSlim call, 500 by default.
+	PageSize int
+}
+
+// Export writes the rows of the table matching the filter to w and returns how many were written.
+func Export(ctx context.Context, ucodeApi ucodesdk.UcodeApis, w io.Writer, opts Options) (int, error) {
+	if opts.TableSlug == "" {
+		return 0, fmt.Errorf("table slug is required")
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultPageSize
+	}
+
+	var (
+		writer rowWriter
+		err    error
+	)
+	switch opts.Format {
+	case "", FormatCSV:
+		writer = &csvWriter{w: csv.NewWriter(w)}
+	case FormatNDJSON:
+		writer = newNDJSONWriter(w)
+	case FormatXLSX:
+		if writer, err = newXLSXWriter(w); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("unknown format %q", opts.Format)
+	}
+
+	var (
+		written int
+		columns = opts.Columns
+		// header is set when the columns come from the first page
+		header map[string]bool
+	)
+
+	err = Walk(ctx, ucodeApi, opts, func(page []map[string]interface{}) error {
+		rows := make([]map[string]interface{}, len(page))
+		for i, object := range page {
+			rows[i] = Flatten(object)
+		}
+
+		if columns == nil && opts.Format != FormatNDJSON {
+			columns = columnsOf(rows)
+			header = make(map[string]bool, len(columns))
+			for _, column := range columns {
+				header[column] = true
+			}
+		}
+		if written == 0 {
+			if err := writer.WriteHeader(columns); err != nil {
+				return err
+			}
+		}
+
+		for _, row := range rows {
+			for column := range row {
+				if header != nil && !header[column] {
+					return fmt.Errorf("row %d: %q: %w", written+1, column, ErrUnknownColumn)
+				}
+			}
+
+			rowColumns := columns
+			if rowColumns == nil {
+				rowColumns = columnsOf([]map[string]interface{}{row})
+			}
+
+			values := make([]interface{}, len(rowColumns))
+			for i, column := range rowColumns {
+				values[i] = row[column]
+			}
+
+			if err := writer.WriteRow(rowColumns, values); err != nil {
+				return err
+			}
+			written++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return written, err
+	}
+
+	if written == 0 && len(columns) > 0 {
+		// an empty table still gets its header
+		if err = writer.WriteHeader(columns); err != nil {
+			return 0, err
+		}
+	}
+
+	return written, writer.Close()
+}
+
+/*
+Walk calls fn with every page of the table matching opts.Filter until the last page or an error.
+
+The last page is the one reaching the count of the response, or an empty page when
+the response has no count. A page shorter than the limit only means the server caps
+the limit, so the next pages are asked with a limit the rows read so far divide,
+since GetListSlim sends the offset as (page-1)*limit.
+*/
+func Walk(ctx context.Context, ucodeApi ucodesdk.UcodeApis, opts Options, fn func(page []map[string]interface{}) error) error {
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultPageSize
+	}
+
+	for read, limit := 0, opts.PageSize; ; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		filter := make(map[string]interface{}, len(opts.Filter))
+		for key, value := range opts.Filter {
+			filter[key] = value
+		}
+
+		page := read/limit + 1
+		list, _, err := ucodeApi.GetListSlim(&ucodesdk.ArgumentWithPegination{
+			AppId:       opts.AppId,
+			TableSlug:   opts.TableSlug,
+			Request:     ucodesdk.Request{Data: filter},
+			DisableFaas: opts.DisableFaas,
+			Limit:       limit,
+			Page:        page,
+		})
+		if err != nil {
+			return fmt.Errorf("getting page %d of %s: %w", page, opts.TableSlug, err)
+		}
+
+		rows := list.Data.Data.Response
+		if len(rows) == 0 {
+			return nil
+		}
+		if err = fn(rows); err != nil {
+			return err
+		}
+
+		read += len(rows)
+		if total, ok := list.Total(); ok && read >= total {
+			return nil
+		}
+		if len(rows) < limit {
+			limit = gcd(read, len(rows))
+		}
+	}
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
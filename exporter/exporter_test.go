@@ -0,0 +1,147 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId)
+		ctx      = context.Background()
+	)
+	for i := 0; i < 7; i++ {
+		server.Seed(appId, "houses", map[string]interface{}{
+			"guid":  fmt.Sprintf("guid-%d", i),
+			"name":  fmt.Sprintf("house, %d", i),
+			"price": 15000 + i,
+			"room":  map[string]interface{}{"name": "room", "size": 12.5},
+			"tags":  []string{"a", "b"},
+		})
+	}
+	server.Seed(appId, "houses", map[string]interface{}{"guid": "other", "name": "other", "price": 1})
+
+	t.Run("csv", func(t *testing.T) {
+		var out bytes.Buffer
+
+		written, err := Export(ctx, ucodeApi, &out, Options{
+			TableSlug: "houses",
+			Filter:    map[string]interface{}{"room": map[string]interface{}{"$ne": nil}},
+			PageSize:  3,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 7, written)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Len(t, lines, 8)
+		assert.Equal(t, "guid,name,price,room.name,room.size,tags", lines[0])
+		assert.Equal(t, `guid-0,"house, 0",15000,room,12.5,"[""a"",""b""]"`, lines[1])
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		var out bytes.Buffer
+
+		written, err := Export(ctx, ucodeApi, &out, Options{TableSlug: "houses", Format: FormatNDJSON, Columns: []string{"name", "room.size"}})
+		assert.NoError(t, err)
+		assert.Equal(t, 8, written)
+
+		scanner := bufio.NewScanner(&out)
+		scanner.Scan()
+		var row map[string]interface{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+		assert.Equal(t, map[string]interface{}{"name": "house, 0", "room.size": 12.5}, row)
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		var out bytes.Buffer
+
+		_, err := Export(ctx, ucodeApi, &out, Options{TableSlug: "houses", Format: FormatXLSX, Columns: []string{"name", "price"}})
+		assert.NoError(t, err)
+
+		archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+		assert.NoError(t, err)
+
+		sheet, err := archive.Open("xl/worksheets/sheet1.xml")
+		assert.NoError(t, err)
+		content, _ := io.ReadAll(sheet)
+		assert.Contains(t, string(content), `<c r="A2" t="inlineStr"><is><t xml:space="preserve">house, 0</t></is></c><c r="B2"><v>15000</v></c>`)
+		assert.Contains(t, string(content), `<row r="9">`)
+	})
+
+	assert.Equal(t, "AB", columnName(27))
+}
+
+func TestWalkLimitCap(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	appId := "test_app_id"
+	for i := 0; i < 11; i++ {
+		server.Seed(appId, "houses", map[string]interface{}{"guid": fmt.Sprintf("guid-%02d", i)})
+	}
+
+	// the server returns at most 4 rows, whatever the limit
+	capped := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 4 {
+			query.Set("limit", "4")
+			r.URL.RawQuery = query.Encode()
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer capped.Close()
+
+	var guids []string
+	err := Walk(context.Background(), ucodesdk.New(&ucodesdk.Config{BaseURL: capped.URL, AppId: appId}), Options{TableSlug: "houses", PageSize: 10}, func(page []map[string]interface{}) error {
+		for _, row := range page {
+			guids = append(guids, row["guid"].(string))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, guids, 11) {
+		assert.Equal(t, "guid-04", guids[4])
+		assert.Equal(t, "guid-10", guids[10])
+	}
+}
+
+func TestExportUnknownColumn(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	appId := "test_app_id"
+	server.Seed(appId, "houses",
+		map[string]interface{}{"guid": "guid-1", "name": "house 1"},
+		map[string]interface{}{"guid": "guid-2", "name": "house 2", "price": 15000},
+	)
+
+	var out bytes.Buffer
+	written, err := Export(context.Background(), server.Client(appId), &out, Options{TableSlug: "houses", PageSize: 1})
+	assert.ErrorIs(t, err, ErrUnknownColumn)
+	assert.Contains(t, err.Error(), `"price"`)
+	assert.Equal(t, 1, written)
+
+	out.Reset()
+	written, err = Export(context.Background(), server.Client(appId), &out, Options{TableSlug: "houses", PageSize: 1, Columns: []string{"name", "price"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, written)
+	assert.Equal(t, "name,price\nhouse 1,\nhouse 2,15000\n", out.String())
+}
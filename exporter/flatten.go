@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cast"
+)
+
+/*
+Flatten turns nested objects, like the expanded relations of a row, into dotted keys:
+
+	{"name": "house", "room": {"name": "a", "size": 12}} -> {"name": "house", "room.name": "a", "room.size": 12}
+
+Arrays are kept as they are.
+*/
+func Flatten(object map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{}, len(object))
+	flatten("", object, flat)
+	return flat
+}
+
+func flatten(prefix string, object map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range object {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flatten(key, nested, flat)
+			continue
+		}
+
+		flat[key] = value
+	}
+}
+
+// columnsOf returns the keys of the rows sorted with guid first.
+func columnsOf(rows []map[string]interface{}) []string {
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for key := range row {
+			seen[key] = true
+		}
+	}
+
+	columns := make([]string, 0, len(seen))
+	for key := range seen {
+		if key != "guid" {
+			columns = append(columns, key)
+		}
+	}
+	sort.Strings(columns)
+
+	if seen["guid"] {
+		columns = append([]string{"guid"}, columns...)
+	}
+
+	return columns
+}
+
+// formatCell formats a value for a text cell, arrays and objects are written as JSON.
+func formatCell(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case []interface{}, map[string]interface{}:
+		valueByte, _ := json.Marshal(value)
+		return string(valueByte)
+	default:
+		return cast.ToString(value)
+	}
+}
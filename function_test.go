@@ -124,6 +124,17 @@ func TestEndToEnd(t *testing.T) {
 		}
 	})
 
+	// the probe must never take a Postgres app for a Mongo app, an unknown backend is sent like Mongo
+	t.Run("Capabilities", func(t *testing.T) {
+		capabilities, err := ucodeApi.Info().Capabilities(mongoAppId)
+		assert.NoError(t, err)
+		assert.Equal(t, BackendMongo, capabilities.Backend)
+
+		capabilities, err = ucodeApi.Info().Capabilities(postgresAppId)
+		assert.NoError(t, err)
+		assert.NotEqual(t, BackendMongo, capabilities.Backend)
+	})
+
 	t.Run("createInMongo", func(t *testing.T) {
 		// --------------------------CreateObject------------------------------
 		// create houses
@@ -568,6 +579,40 @@ func TestEndToEnd(t *testing.T) {
 		}
 	})
 
+	// an array filter matches any of its values, migrate looks objects up by guid this way
+	t.Run("GetListSlim by guids", func(t *testing.T) {
+		for appId, rooms := range map[string][]map[string]interface{}{mongoAppId: roomsMongo, postgresAppId: roomsPostgres} {
+			if len(rooms) < 2 {
+				t.Errorf("not enough rooms in %s", appId)
+				continue
+			}
+
+			guids := []string{cast.ToString(rooms[0]["guid"]), cast.ToString(rooms[1]["guid"])}
+			getListSlim, response, err := ucodeApi.GetListSlim(&ArgumentWithPegination{
+				AppId:       appId,
+				TableSlug:   "room",
+				Request:     Request{Data: map[string]interface{}{"guid": guids}},
+				DisableFaas: true,
+				Limit:       10,
+				Page:        1,
+			})
+			if err != nil {
+				errorResponse.Description = response.Data["description"]
+				errorResponse.ClientErrorMessage = "Error on GetListSlim"
+				errorResponse.ErrorMessage = err.Error()
+				errorResponse.StatusCode = http.StatusInternalServerError
+				t.Error(returnError(errorResponse))
+				continue
+			}
+
+			var found []string
+			for _, room := range getListSlim.Data.Data.Response {
+				found = append(found, cast.ToString(room["guid"]))
+			}
+			assert.ElementsMatch(t, guids, found)
+		}
+	})
+
 	t.Run("updateHousesInMongo", func(t *testing.T) {
 		// --------------------------UpdateObject------------------------------
 		// update first house
@@ -2,10 +2,13 @@ package ucodesdk
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/spf13/cast"
 )
 
 type UcodeApis interface {
@@ -60,6 +63,8 @@ type UcodeApis interface {
 
 		pipelines=[]map[string]interface{}{} as filter
 
+		On Postgres apps (see Capabilities) it returns ErrUnsupportedOnBackend without sending anything.
+
 		Works for [Mongo]
 	*/
 	GetListAggregation(arg *Argument) (GetListAggregationClientApiResponse, Response, error)
@@ -97,7 +102,9 @@ type UcodeApis interface {
 		"id_from":    "table_id", 		// main table id
 		"id_to":      "table_id",		// relation table id
 
-		Works for [Mongo]
+		On Postgres apps (see Capabilities) the <table_to>_ids field of the object is updated instead.
+
+		Works for [Mongo, Postgres]
 	*/
 	AppendManyToMany(arg *Argument) (Response, error)
 	/*
@@ -108,17 +115,63 @@ type UcodeApis interface {
 		"id_from":    "table_id", 		// main table id
 		"id_to":      "table_id",		// relation table id
 
-		Works for [Mongo]
+		On Postgres apps (see Capabilities) the <table_to>_ids field of the object is updated instead,
+		nothing is changed when id_to is empty.
+
+		Works for [Mongo, Postgres]
 	*/
 	DeleteManyToMany(arg *Argument) (Response, error)
-
+	/*
+		V2 is a function that returns the API whose methods take a context and return
+		the objects directly, e.g. (Object, error) and ([]Object, PageInfo, error)
+	*/
+	V2() V2Apis
+	/*
+		Relations is a function that returns the API adding, removing, setting and listing
+		the many-to-many links of an object
+	*/
+	Relations() Relations
+	/*
+		Bulk is a function that returns the API creating, updating, deleting, upserting
+		and streaming many objects at once
+	*/
+	Bulk() BulkApis
+	/*
+		Functions is a function that returns the API invoking other uCode functions
+	*/
+	Functions() FunctionApis
+	/*
+		Info is a function that returns the API reporting the capabilities of apps
+		and the read stats of the client
+	*/
+	Info() InfoApis
 	Config() *Config
 
 	DoRequest(url string, method string, body interface{}, headers map[string]string) ([]byte, error)
 }
 
+// InfoApis reports what the client learned about apps and its own reads.
+type InfoApis interface {
+	/*
+		Capabilities is a function that returns the database of the app and the methods it supports.
+		It is taken from Config.Backend or Config.Backends. When they do not have it, one probe
+		request, an aggregation of the role table, is sent: a Mongo app answers it and is kept
+		for the base url and app id, any other answer leaves the database unknown for
+		Config.BackendRetry. Capabilities.Backend is empty, with the error of the probe when
+		it failed, when the database is unknown.
+	*/
+	Capabilities(appId string) (Capabilities, error)
+	/*
+		ReadStats is a function that returns how many reads were sent, coalesced with an identical
+		read in flight or answered from the cache since the client was created
+	*/
+	ReadStats() ReadStats
+}
+
 type object struct {
-	config *Config
+	config       *Config
+	flights      flightGroup
+	readCounters readCounters
 }
 
 func New(cfg *Config) UcodeApis {
@@ -146,13 +199,22 @@ func (o *object) CreateObject(arg *Argument) (Datas, Response, error) {
 		"X-API-KEY":     appId,
 	}
 
-	createObjectResponseInByte, err := o.DoRequest(url, "POST", arg.Request, header)
+	defer o.invalidate(appId, arg.TableSlug)
+
+	var existing map[string]interface{}
+	_, createObjectResponseInByte, err := o.sendIdempotent(context.Background(), url, "POST", arg.Request, header, arg.IdempotencyKey, o.createLookup(arg, appId, &existing))
 	if err != nil {
 		response.Data = map[string]interface{}{"description": string(createObjectResponseInByte), "message": "Can't send request", "error": err.Error()}
 		response.Status = "error"
 		return Datas{}, response, err
 	}
 
+	// an earlier attempt created the object
+	if existing != nil {
+		createdObject.Data.Data.Data = existing
+		return createdObject, response, nil
+	}
+
 	err = json.Unmarshal(createObjectResponseInByte, &createdObject)
 	if err != nil {
 		response.Data = map[string]interface{}{"description": string(createObjectResponseInByte), "message": "Error while unmarshalling create object", "error": err.Error()}
@@ -199,7 +261,7 @@ func (o *object) GetList(arg *ArgumentWithPegination) (GetListClientApiResponse,
 		"X-API-KEY":     appId,
 	}
 
-	getListResponseInByte, err := o.DoRequest(url, "POST", arg.Request, header)
+	_, getListResponseInByte, err := o.read(context.Background(), appId, arg.TableSlug, url, "POST", arg.Request, header, arg.Request.IsCached, !arg.DisableCoalescing)
 	if err != nil {
 		response.Data = map[string]interface{}{"description": string(getListResponseInByte), "message": "Can't sent request", "error": err.Error()}
 		response.Status = "error"
@@ -213,6 +275,8 @@ func (o *object) GetList(arg *ArgumentWithPegination) (GetListClientApiResponse,
 		return GetListClientApiResponse{}, response, err
 	}
 
+	getListObject.page = arg.pageInfo().complete(getListObject)
+
 	return getListObject, response, nil
 }
 
@@ -246,7 +310,7 @@ func (o *object) GetListSlim(arg *ArgumentWithPegination) (GetListClientApiRespo
 		limit = 10
 	}
 
-	url = fmt.Sprintf("%s&data=%s&offset=%d&limit=%d", url, string(reqObject), (page-1)*limit, limit)
+	url = fmt.Sprintf("%s&data=%s&offset=%d&limit=%d", url, escapeQuery(reqObject), (page-1)*limit, limit)
 
 	var appId = o.config.AppId
 	if arg.AppId != "" {
@@ -258,7 +322,7 @@ func (o *object) GetListSlim(arg *ArgumentWithPegination) (GetListClientApiRespo
 		"X-API-KEY":     appId,
 	}
 
-	getListResponseInByte, err := o.DoRequest(url, "GET", nil, header)
+	_, getListResponseInByte, err := o.read(context.Background(), appId, arg.TableSlug, url, "GET", nil, header, arg.Request.IsCached, !arg.DisableCoalescing)
 	if err != nil {
 		response.Data = map[string]interface{}{"description": string(getListResponseInByte), "message": "Can't sent request", "error": err.Error()}
 		response.Status = "error"
@@ -272,6 +336,8 @@ func (o *object) GetListSlim(arg *ArgumentWithPegination) (GetListClientApiRespo
 		return GetListClientApiResponse{}, response, err
 	}
 
+	listSlim.page = arg.pageInfo().complete(listSlim)
+
 	return listSlim, response, nil
 }
 
@@ -292,7 +358,7 @@ func (o *object) GetSingle(arg *Argument) (ClientApiResponse, Response, error) {
 		"X-API-KEY":     appId,
 	}
 
-	resByte, err := o.DoRequest(url, "GET", nil, header)
+	_, resByte, err := o.read(context.Background(), appId, arg.TableSlug, url, "GET", nil, header, arg.Request.IsCached, !arg.DisableCoalescing)
 	if err != nil {
 		response.Data = map[string]interface{}{"description": string(resByte), "message": "Can't sent request", "error": err.Error()}
 		response.Status = "error"
@@ -326,7 +392,7 @@ func (o *object) GetSingleSlim(arg *Argument) (ClientApiResponse, Response, erro
 		"X-API-KEY":     appId,
 	}
 
-	resByte, err := o.DoRequest(url, "GET", nil, header)
+	_, resByte, err := o.read(context.Background(), appId, arg.TableSlug, url, "GET", nil, header, arg.Request.IsCached, !arg.DisableCoalescing)
 	if err != nil {
 		response.Data = map[string]interface{}{"description": string(resByte), "message": "Can't sent request", "error": err.Error()}
 		response.Status = "error"
@@ -360,7 +426,13 @@ func (o *object) GetListAggregation(arg *Argument) (GetListAggregationClientApiR
 		"X-API-KEY":     appId,
 	}
 
-	getListAggregationResponseInByte, err := o.DoRequest(url, "POST", arg.Request, header)
+	if err := o.supports(context.Background(), appId, "GetListAggregation", func(c Capabilities) bool { return c.Aggregation }); err != nil {
+		response.Data = map[string]interface{}{"message": "Can't sent request", "error": err.Error()}
+		response.Status = "error"
+		return GetListAggregationClientApiResponse{}, response, err
+	}
+
+	_, getListAggregationResponseInByte, err := o.read(context.Background(), appId, arg.TableSlug, url, "POST", arg.Request, header, arg.Request.IsCached, !arg.DisableCoalescing)
 	if err != nil {
 		response.Data = map[string]interface{}{"description": string(getListAggregationResponseInByte), "message": "Can't sent request", "error": err.Error()}
 		response.Status = "error"
@@ -396,7 +468,9 @@ func (o *object) UpdateObject(arg *Argument) (ClientApiUpdateResponse, Response,
 		"X-API-KEY":     appId,
 	}
 
-	updateObjectResponseInByte, err := o.DoRequest(url, "PUT", arg.Request, header)
+	defer o.invalidate(appId, arg.TableSlug)
+
+	_, updateObjectResponseInByte, err := o.sendIdempotent(context.Background(), url, "PUT", arg.Request, header, arg.IdempotencyKey, nil)
 	if err != nil {
 		response.Data = map[string]interface{}{"description": string(updateObjectResponseInByte), "message": "Error while updating object", "error": err.Error()}
 		response.Status = "error"
@@ -432,7 +506,9 @@ func (o *object) MultipleUpdate(arg *Argument) (ClientApiMultipleUpdateResponse,
 		"X-API-KEY":     appId,
 	}
 
-	multipleUpdateObjectsResponseInByte, err := o.DoRequest(url, "PUT", arg.Request, header)
+	defer o.invalidate(appId, arg.TableSlug)
+
+	_, multipleUpdateObjectsResponseInByte, err := o.sendIdempotent(context.Background(), url, "PUT", arg.Request, header, arg.IdempotencyKey, nil)
 	if err != nil {
 		response.Data = map[string]interface{}{"description": string(multipleUpdateObjectsResponseInByte), "message": "Error while multiple updating objects", "error": err.Error()}
 		response.Status = "error"
@@ -467,6 +543,8 @@ func (o *object) Delete(arg *Argument) (Response, error) {
 		"X-API-KEY":     appId,
 	}
 
+	defer o.invalidate(appId, arg.TableSlug)
+
 	_, err := o.DoRequest(url, "DELETE", Request{Data: map[string]interface{}{}}, header)
 	if err != nil {
 		response.Data = map[string]interface{}{"message": "Error while deleting object", "error": err.Error()}
@@ -495,6 +573,8 @@ func (o *object) MultipleDelete(arg *Argument) (Response, error) {
 		"X-API-KEY":     appId,
 	}
 
+	defer o.invalidate(appId, arg.TableSlug)
+
 	_, err := o.DoRequest(url, "DELETE", arg.Request.Data, header)
 	if err != nil {
 		response.Data = map[string]interface{}{"message": "Error while deleting objects", "error": err.Error()}
@@ -521,6 +601,18 @@ func (o *object) AppendManyToMany(arg *Argument) (Response, error) {
 		"X-API-KEY":     appId,
 	}
 
+	if o.backend(context.Background(), appId) == BackendPostgres {
+		if err := o.manyToMany(arg, appId, false); err != nil {
+			response.Data = map[string]interface{}{"message": "Error while appending many-to-many object", "error": err.Error()}
+			response.Status = "error"
+			return response, err
+		}
+
+		return response, nil
+	}
+
+	defer o.invalidate(appId, arg.TableSlug, cast.ToString(arg.Request.Data["table_from"]), cast.ToString(arg.Request.Data["table_to"]))
+
 	_, err := o.DoRequest(url, "PUT", arg.Request.Data, header)
 	if err != nil {
 		response.Data = map[string]interface{}{"message": "Error while appending many-to-many object", "error": err.Error()}
@@ -547,6 +639,18 @@ func (o *object) DeleteManyToMany(arg *Argument) (Response, error) {
 		"X-API-KEY":     appId,
 	}
 
+	if o.backend(context.Background(), appId) == BackendPostgres {
+		if err := o.manyToMany(arg, appId, true); err != nil {
+			response.Data = map[string]interface{}{"message": "Error while deleting many-to-many object", "error": err.Error()}
+			response.Status = "error"
+			return response, err
+		}
+
+		return response, nil
+	}
+
+	defer o.invalidate(appId, arg.TableSlug, cast.ToString(arg.Request.Data["table_from"]), cast.ToString(arg.Request.Data["table_to"]))
+
 	_, err := o.DoRequest(url, "DELETE", arg.Request.Data, header)
 	if err != nil {
 		response.Data = map[string]interface{}{"message": "Error while deleting many-to-many object", "error": err.Error()}
@@ -564,17 +668,50 @@ It gets url, method, body, app_id(for ucode purpose) as paramters
 Returns body of the response as array of bytes and error
 */
 func (o *object) DoRequest(url string, method string, body interface{}, headers map[string]string) ([]byte, error) {
+	_, respByte, err := o.send(context.Background(), url, method, body, headers)
+	return respByte, err
+}
+
+// HTTPError is returned by helpers which check the status code of the response.
+type HTTPError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// send is DoRequest bound to ctx which also returns the status code of the response.
+func (o *object) send(ctx context.Context, url string, method string, body interface{}, headers map[string]string) (int, []byte, error) {
+	resp, err := o.do(ctx, url, method, body, headers)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respByte, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, respByte, err
+}
+
+// do sends the request and returns the response with its body decompressed and limited to Config.MaxResponseSize.
+func (o *object) do(ctx context.Context, url string, method string, body interface{}, headers map[string]string) (*http.Response, error) {
 	data, err := json.Marshal(&body)
 	if err != nil {
 		return nil, err
 	}
 
+	data, compressed, err := o.compress(data)
+	if err != nil {
+		return nil, err
+	}
+
 	client := &http.Client{}
 	if o.config.RequestTimeout > 0 {
 		client.Timeout = o.config.RequestTimeout
 	}
 
-	request, err := http.NewRequest(method, url, bytes.NewBuffer(data))
+	request, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
 	}
@@ -583,15 +720,29 @@ func (o *object) DoRequest(url string, method string, body interface{}, headers
 	for key, value := range headers {
 		request.Header.Add(key, value)
 	}
+	if compressed {
+		request.Header.Set("Content-Encoding", gzipEncoding)
+	}
+	request.Header.Set("Accept-Encoding", gzipEncoding)
 
 	resp, err := client.Do(request)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respByte, err := io.ReadAll(resp.Body)
-	return respByte, err
+	if err = decompress(resp); err != nil {
+		return nil, err
+	}
+
+	if max := o.config.MaxResponseSize; max > 0 {
+		if resp.ContentLength > max {
+			resp.Body.Close()
+			return nil, tooLarge(max)
+		}
+		resp.Body = &limitedBody{ReadCloser: resp.Body, limit: max, remaining: max}
+	}
+
+	return resp, nil
 }
 
 func (o *object) Config() *Config {
@@ -0,0 +1,154 @@
+package ucodesdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader carries the idempotency key of create and update requests.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	defaultMaxAttempts = 3
+	defaultBackoff     = 200 * time.Millisecond
+)
+
+type (
+	/*
+		RetryPolicy retries create and update requests which failed with a network error,
+		a timeout, status 429 or a 5xx status. Every attempt of an operation sends the same
+		idempotency key.
+	*/
+	RetryPolicy struct {
+		// MaxAttempts is the number of attempts including the first one, 3 by default.
+		MaxAttempts int
+		// Backoff is the wait before the second attempt, it doubles for every next one, 200ms by default.
+		Backoff time.Duration
+		/*
+			UniqueFields maps table slugs to a field with unique values. Before a CreateObject
+			is sent again, the table is searched for an object with the value of that field,
+			so a create which succeeded on the server but failed on the client is not repeated.
+		*/
+		UniqueFields map[string]string
+	}
+
+	idempotencyKeyContext struct{}
+)
+
+// WithIdempotencyKey returns a context whose key is sent by CreateMany, UpdateMany and UpsertMany. Every chunk gets its own key derived from it.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContext{}, key)
+}
+
+// IdempotencyKey returns the idempotency key of ctx, or an empty string.
+func IdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContext{}).(string)
+	return key
+}
+
+func (p *RetryPolicy) withDefaults() RetryPolicy {
+	if p == nil {
+		return RetryPolicy{MaxAttempts: 1}
+	}
+
+	result := *p
+	if result.MaxAttempts <= 0 {
+		result.MaxAttempts = defaultMaxAttempts
+	}
+	if result.Backoff <= 0 {
+		result.Backoff = defaultBackoff
+	}
+
+	return result
+}
+
+/*
+sendIdempotent sends a create or update request with the idempotency key, a new one when key is empty,
+and retries it according to Config.Retry. Before a retry, exists is called when it is not nil;
+when it reports true the request is not sent again and no body is returned.
+*/
+func (o *object) sendIdempotent(ctx context.Context, url, method string, body interface{}, headers map[string]string, key string, exists func(ctx context.Context) (bool, error)) (int, []byte, error) {
+	if key == "" {
+		key = NewGUID()
+	}
+
+	withKey := make(map[string]string, len(headers)+1)
+	for name, value := range headers {
+		withKey[name] = value
+	}
+	withKey[IdempotencyKeyHeader] = key
+
+	var (
+		policy  = o.config.Retry.withDefaults()
+		backoff = policy.Backoff
+	)
+
+	for attempt := 1; ; attempt++ {
+		statusCode, respByte, err := o.send(ctx, url, method, body, withKey)
+		if attempt >= policy.MaxAttempts || !retryable(ctx, statusCode, err) {
+			return statusCode, respByte, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return statusCode, respByte, err
+		}
+		backoff *= 2
+
+		if exists == nil {
+			continue
+		}
+
+		// the failed attempt may have reached the server, sending it blindly could create a duplicate
+		found, lookupErr := exists(ctx)
+		if lookupErr != nil {
+			if err == nil {
+				err = &HTTPError{StatusCode: statusCode, Body: respByte}
+			}
+			return statusCode, respByte, fmt.Errorf("%w, looking for the object before retrying: %v", err, lookupErr)
+		}
+		if found {
+			return http.StatusOK, nil, nil
+		}
+	}
+}
+
+// retryable reports whether a failed attempt is worth repeating.
+func retryable(ctx context.Context, statusCode int, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return !errors.Is(err, ErrResponseTooLarge)
+	}
+
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// createLookup returns the exists function of a CreateObject, it stores the found object in existing.
+func (o *object) createLookup(arg *Argument, appId string, existing *map[string]interface{}) func(ctx context.Context) (bool, error) {
+	if o.config.Retry == nil {
+		return nil
+	}
+
+	field := o.config.Retry.UniqueFields[arg.TableSlug]
+	value, ok := arg.Request.Data[field]
+	if field == "" || !ok || value == nil || value == "" {
+		return nil
+	}
+
+	return func(ctx context.Context) (bool, error) {
+		objects, err := o.listSlim(ctx, appId, arg.TableSlug, map[string]interface{}{field: value}, 0, 1, arg.DisableFaas)
+		if err != nil || len(objects) == 0 {
+			return false, err
+		}
+
+		*existing = objects[0]
+		return true, nil
+	}
+}
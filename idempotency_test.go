@@ -0,0 +1,116 @@
+package ucodesdk_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+// lossyServer stores the first createsLost creates in the backend but answers them with 502.
+type lossyServer struct {
+	backend     *ucodetest.Backend
+	mu          sync.Mutex
+	createsLost int
+	keys        []string
+}
+
+func (s *lossyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.keys = append(s.keys, r.Header.Get(ucodesdk.IdempotencyKeyHeader))
+	lose := r.Method != http.MethodGet && s.createsLost > 0
+	if lose {
+		s.createsLost--
+	}
+	s.mu.Unlock()
+
+	if !lose {
+		s.backend.ServeHTTP(w, r)
+		return
+	}
+
+	s.backend.ServeHTTP(httptest.NewRecorder(), r)
+	w.WriteHeader(http.StatusBadGateway)
+}
+
+func TestIdempotency(t *testing.T) {
+	var (
+		appId   = "test_app_id"
+		backend = ucodetest.NewBackend()
+		lossy   = &lossyServer{backend: backend}
+		server  = httptest.NewServer(lossy)
+		retry   = &ucodesdk.RetryPolicy{Backoff: time.Millisecond}
+	)
+	defer server.Close()
+
+	ucodeApi := ucodesdk.New(&ucodesdk.Config{BaseURL: server.URL, AppId: appId, Retry: retry})
+	reset := func(createsLost int) {
+		backend.Reset()
+		lossy.createsLost, lossy.keys = createsLost, nil
+	}
+
+	t.Run("key is reused across retries", func(t *testing.T) {
+		reset(2)
+		backend.Seed(appId, "houses", map[string]interface{}{"guid": "1"})
+
+		_, _, err := ucodeApi.UpdateObject(&ucodesdk.Argument{
+			TableSlug:      "houses",
+			Request:        ucodesdk.Request{Data: map[string]interface{}{"guid": "1", "name": "house"}},
+			IdempotencyKey: "update-1",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"update-1", "update-1", "update-1"}, lossy.keys)
+	})
+
+	t.Run("generated key", func(t *testing.T) {
+		reset(1)
+
+		_, _, err := ucodeApi.CreateObject(&ucodesdk.Argument{TableSlug: "room", Request: ucodesdk.Request{Data: map[string]interface{}{"name": "room"}}})
+		assert.NoError(t, err)
+		if assert.Len(t, lossy.keys, 2) {
+			assert.NotEmpty(t, lossy.keys[0])
+			assert.Equal(t, lossy.keys[0], lossy.keys[1])
+		}
+		// without a unique field the create is sent again, the backend answers it by its key
+		assert.Len(t, backend.Objects(appId, "room"), 1)
+	})
+
+	t.Run("dedupe by unique field", func(t *testing.T) {
+		reset(1)
+		retry.UniqueFields = map[string]string{"houses": "external_id"}
+		defer func() { retry.UniqueFields = nil }()
+
+		created, _, err := ucodeApi.CreateObject(&ucodesdk.Argument{
+			TableSlug: "houses",
+			Request:   ucodesdk.Request{Data: map[string]interface{}{"external_id": "h-1", "name": "house"}},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, backend.Objects(appId, "houses"), 1)
+		assert.Equal(t, "h-1", created.Data.Data.Data["external_id"])
+		assert.NotEmpty(t, created.Data.Data.Data["guid"])
+	})
+
+	t.Run("chunk keys", func(t *testing.T) {
+		reset(1)
+		backend.Seed(appId, "houses", map[string]interface{}{"guid": "a"}, map[string]interface{}{"guid": "b"})
+
+		ctx := ucodesdk.WithIdempotencyKey(context.Background(), "import-1")
+		_, err := ucodeApi.Bulk().UpdateMany(ctx, "houses", []map[string]interface{}{{"guid": "a"}, {"guid": "b"}}, &ucodesdk.BulkOptions{ChunkSize: 1, Concurrency: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"import-1:a", "import-1:a", "import-1:b"}, lossy.keys)
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		reset(5)
+
+		_, err := ucodeApi.Bulk().CreateMany(context.Background(), "houses", []map[string]interface{}{{"name": "house"}}, nil)
+		assert.Error(t, err)
+		assert.Len(t, lossy.keys, 3)
+	})
+}
@@ -0,0 +1,255 @@
+/*
+Package importer imports CSV files into uCode tables.
+
+Rows are read in batches, mapped to field slugs and coerced with spf13/cast,
+then written with CreateMany, or UpsertMany when the mapping has key fields.
+Rows which fail validation or writing are copied to a rejects CSV with an
+extra "error" column, so they can be fixed and imported again.
+*/
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+)
+
+const defaultBatchSize = 1000
+
+type (
+	Options struct {
+		TableSlug string
+		Mapping   Mapping
+		// Bulk configures the writes, see ucodesdk.BulkOptions.
+		Bulk *ucodesdk.BulkOptions
+		// BatchSize is the number of rows read before they are written, 1000 by default.
+		BatchSize int
+		// Comma is the field delimiter, ',' by default.
+		Comma rune
+		// Rejects receives the rejected rows, they are dropped when it is nil.
+		Rejects io.Writer
+		// DryRun validates the rows without writing them, valid rows are counted as created.
+		DryRun bool
+	}
+
+	Report struct {
+		Rows     int
+		Created  int
+		Updated  int
+		Rejected int
+	}
+
+	// row is a data row of the file with its line number.
+	row struct {
+		line   int
+		cells  []string
+		object map[string]interface{}
+	}
+)
+
+/*
+Import reads CSV with a header row from r and writes its rows to the table.
+
+Rejected rows do not fail the import, they are counted in the report and written to opts.Rejects.
+An error is returned when the file, the mapping or the rejects writer is unusable.
+*/
+func Import(ctx context.Context, ucodeApi ucodesdk.UcodeApis, r io.Reader, opts Options) (Report, error) {
+	var report Report
+
+	if opts.TableSlug == "" {
+		return report, errors.New("table slug is required")
+	}
+	if err := opts.Mapping.validate(); err != nil {
+		return report, err
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+
+	reader := csv.NewReader(r)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return report, fmt.Errorf("reading header: %w", err)
+	}
+	if len(header) > 0 {
+		// spreadsheet exports often start with a byte order mark
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	columns := make([]*Column, len(header))
+	for i, name := range header {
+		if column, ok := opts.Mapping.Columns[strings.TrimSpace(name)]; ok {
+			columns[i] = &column
+		}
+	}
+	for name, column := range opts.Mapping.Columns {
+		if column.Required && !containsTrimmed(header, name) {
+			return report, fmt.Errorf("required column %q is missing", name)
+		}
+	}
+
+	var rejects *csv.Writer
+	if opts.Rejects != nil {
+		rejects = csv.NewWriter(opts.Rejects)
+		if err = rejects.Write(append(append([]string(nil), header...), "error")); err != nil {
+			return report, err
+		}
+	}
+
+	reject := func(r row, rowErr error) error {
+		report.Rejected++
+		if rejects == nil {
+			return nil
+		}
+		return rejects.Write(append(append([]string(nil), r.cells...), fmt.Sprintf("line %d: %v", r.line, rowErr)))
+	}
+
+	var (
+		batch []row
+		line  = 1
+	)
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return report, err
+			}
+			report.Rows++
+			if err = reject(row{line: line, cells: cells}, err); err != nil {
+				return report, err
+			}
+			continue
+		}
+
+		report.Rows++
+
+		object, err := convertRow(cells, header, columns)
+		if err != nil {
+			if err = reject(row{line: line, cells: cells}, err); err != nil {
+				return report, err
+			}
+			continue
+		}
+
+		batch = append(batch, row{line: line, cells: cells, object: object})
+		if len(batch) >= opts.BatchSize {
+			if err = writeBatch(ctx, ucodeApi, batch, opts, &report, reject); err != nil {
+				return report, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if err = writeBatch(ctx, ucodeApi, batch, opts, &report, reject); err != nil {
+		return report, err
+	}
+
+	if rejects != nil {
+		rejects.Flush()
+		return report, rejects.Error()
+	}
+
+	return report, nil
+}
+
+func convertRow(cells, header []string, columns []*Column) (map[string]interface{}, error) {
+	object := map[string]interface{}{}
+
+	for i, column := range columns {
+		if column == nil {
+			continue
+		}
+
+		var cell string
+		if i < len(cells) {
+			cell = strings.TrimSpace(cells[i])
+		}
+
+		if cell == "" {
+			if column.Required {
+				return nil, fmt.Errorf("%s is required", header[i])
+			}
+			continue
+		}
+
+		value, err := column.convert(cell)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", header[i], err)
+		}
+		object[column.Field] = value
+	}
+
+	return object, nil
+}
+
+func writeBatch(ctx context.Context, ucodeApi ucodesdk.UcodeApis, batch []row, opts Options, report *Report, reject func(row, error) error) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if opts.DryRun {
+		report.Created += len(batch)
+		return nil
+	}
+
+	objects := make([]map[string]interface{}, len(batch))
+	for i, r := range batch {
+		objects[i] = r.object
+	}
+
+	if len(opts.Mapping.KeyFields) > 0 {
+		results, _ := ucodeApi.Bulk().UpsertMany(ctx, opts.TableSlug, opts.Mapping.KeyFields, objects, opts.Bulk)
+		for i, result := range results {
+			switch {
+			case result.Err != nil:
+				if err := reject(batch[i], result.Err); err != nil {
+					return err
+				}
+			case result.Action == ucodesdk.UpsertUpdated:
+				report.Updated++
+			default:
+				report.Created++
+			}
+		}
+		return ctx.Err()
+	}
+
+	results, _ := ucodeApi.Bulk().CreateMany(ctx, opts.TableSlug, objects, opts.Bulk)
+	for i, result := range results {
+		if result.Err != nil {
+			if err := reject(batch[i], result.Err); err != nil {
+				return err
+			}
+			continue
+		}
+		report.Created++
+	}
+
+	return ctx.Err()
+}
+
+func containsTrimmed(values []string, value string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) == value {
+			return true
+		}
+	}
+
+	return false
+}
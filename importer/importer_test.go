@@ -0,0 +1,82 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImport(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId   = "test_app_id"
+		rejects bytes.Buffer
+		mapping = Mapping{
+			Columns: map[string]Column{
+				"External": {Field: "external_id", Required: true},
+				"Name":     {Field: "name"},
+				"Price":    {Field: "price", Type: TypeFloat},
+				"Rooms":    {Field: "room_count", Type: TypeInt},
+				"Sold":     {Field: "sold", Type: TypeBool},
+				"Built":    {Field: "built_at", Type: TypeDate, Format: "02.01.2006"},
+			},
+			KeyFields: []string{"external_id"},
+		}
+		file = "\ufeffExternal,Name,Price,Rooms,Sold,Built,Ignored\n" +
+			"1,house 1,15000.5,08,yes,01.02.2020,x\n" +
+			"2,house 2,abc,5,no,01.02.2020,x\n" +
+			",house 3,1,1,no,01.02.2020,x\n" +
+			"4,house 4,20 000,3,true,,x\n"
+	)
+	server.Seed(appId, "houses", map[string]interface{}{"external_id": "4", "name": "old"})
+
+	report, err := Import(context.Background(), server.Client(appId), strings.NewReader(file), Options{
+		TableSlug: "houses",
+		Mapping:   mapping,
+		BatchSize: 2,
+		Rejects:   &rejects,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, Report{Rows: 4, Created: 1, Updated: 1, Rejected: 2}, report)
+
+	stored := server.Objects(appId, "houses")
+	if assert.Len(t, stored, 2) {
+		assert.Equal(t, "house 4", stored[0]["name"])
+		assert.EqualValues(t, 20000, stored[0]["price"])
+		assert.Equal(t, true, stored[0]["sold"])
+		assert.EqualValues(t, 8, stored[1]["room_count"])
+		assert.Equal(t, "2020-02-01T00:00:00Z", stored[1]["built_at"])
+		assert.NotContains(t, stored[1], "Ignored")
+	}
+
+	rows, err := csv.NewReader(&rejects).ReadAll()
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 3) {
+		assert.Equal(t, "error", rows[0][7])
+		assert.Equal(t, "2", rows[1][0])
+		assert.Contains(t, rows[1][7], "line 3: Price")
+		assert.Contains(t, rows[2][7], "External is required")
+	}
+}
+
+func TestColumnConvertInt(t *testing.T) {
+	column := Column{Field: "zip", Type: TypeInt}
+
+	for cell, want := range map[string]int64{"010": 10, "08": 8, "09": 9, "1 000": 1000, " 42 ": 42, "-7": -7} {
+		value, err := column.convert(cell)
+		assert.NoError(t, err, cell)
+		assert.Equal(t, want, value, cell)
+	}
+
+	for _, cell := range []string{"0x10", "1.5", "abc"} {
+		_, err := column.convert(cell)
+		assert.Error(t, err, cell)
+	}
+}
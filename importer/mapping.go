@@ -0,0 +1,133 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// Column types understood by Mapping.
+const (
+	TypeString   = "string"
+	TypeInt      = "int"
+	TypeFloat    = "float"
+	TypeBool     = "bool"
+	TypeDate     = "date"
+	TypeDatetime = "datetime"
+)
+
+type (
+	/*
+		Mapping maps CSV columns to field slugs of the table:
+
+			{
+			    "columns": {
+			        "Name":       {"field": "name", "required": true},
+			        "Price":      {"field": "price", "type": "float"},
+			        "Built at":   {"field": "built_at", "type": "date", "format": "02.01.2006"},
+			        "External":   {"field": "external_id"}
+			    },
+			    "key_fields": ["external_id"]
+			}
+
+		Columns missing from the mapping are ignored. With key_fields rows are upserted.
+	*/
+	Mapping struct {
+		Columns   map[string]Column `json:"columns"`
+		KeyFields []string          `json:"key_fields"`
+	}
+
+	Column struct {
+		Field string `json:"field"`
+		// Type is one of string (default), int, float, bool, date and datetime.
+		Type string `json:"type"`
+		// Format is the Go time layout of date and datetime values, any common layout is accepted when empty.
+		Format   string `json:"format"`
+		Required bool   `json:"required"`
+	}
+)
+
+// LoadMapping reads a mapping from a JSON file.
+func LoadMapping(path string) (Mapping, error) {
+	var mapping Mapping
+
+	fileByte, err := os.ReadFile(path)
+	if err != nil {
+		return mapping, err
+	}
+
+	if err = json.Unmarshal(fileByte, &mapping); err != nil {
+		return mapping, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return mapping, mapping.validate()
+}
+
+func (m Mapping) validate() error {
+	if len(m.Columns) == 0 {
+		return fmt.Errorf("mapping has no columns")
+	}
+
+	fields := map[string]bool{}
+	for header, column := range m.Columns {
+		if column.Field == "" {
+			return fmt.Errorf("column %q has no field", header)
+		}
+
+		switch column.Type {
+		case "", TypeString, TypeInt, TypeFloat, TypeBool, TypeDate, TypeDatetime:
+		default:
+			return fmt.Errorf("column %q has unknown type %q", header, column.Type)
+		}
+
+		fields[column.Field] = true
+	}
+
+	for _, field := range m.KeyFields {
+		if !fields[field] {
+			return fmt.Errorf("key field %q is not mapped from any column", field)
+		}
+	}
+
+	return nil
+}
+
+// convert coerces a cell to the type of the column.
+func (c Column) convert(cell string) (interface{}, error) {
+	switch c.Type {
+	case TypeInt:
+		// parsed as decimal, cells like zip codes keep their leading zeros in spreadsheets
+		return strconv.ParseInt(strings.ReplaceAll(strings.TrimSpace(cell), " ", ""), 10, 64)
+	case TypeFloat:
+		return cast.ToFloat64E(strings.ReplaceAll(cell, " ", ""))
+	case TypeBool:
+		switch strings.ToLower(cell) {
+		case "yes", "y", "ha":
+			return true, nil
+		case "no", "n", "yo'q":
+			return false, nil
+		}
+		return cast.ToBoolE(cell)
+	case TypeDate, TypeDatetime:
+		var (
+			value time.Time
+			err   error
+		)
+		if c.Format != "" {
+			value, err = time.Parse(c.Format, cell)
+		} else {
+			value, err = cast.ToTimeE(cell)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return value.Format(time.RFC3339), nil
+	default:
+		return cell, nil
+	}
+}
@@ -0,0 +1,153 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// CorrelationIdHeader carries the id shared by every function invoked while handling one request.
+const CorrelationIdHeader = "X-Correlation-Id"
+
+type correlationIdKey struct{}
+
+type (
+	// FunctionApis invokes other uCode functions, see UcodeApis.Functions.
+	FunctionApis interface {
+		/*
+			InvokeFunction is a function that invokes another uCode function and waits for its response
+
+			payload.AppId defaults to the app id of the config, the correlation id of ctx
+			(see WithCorrelationId) is sent along so the call chain can be traced.
+			An error is returned when the function fails or answers with status "error".
+		*/
+		InvokeFunction(ctx context.Context, name string, payload Data) (Response, error)
+		/*
+			InvokeFunctionAsync is a function that queues an invocation of another uCode function
+			and returns without waiting for it (fire-and-forget)
+		*/
+		InvokeFunctionAsync(ctx context.Context, name string, payload Data) error
+	}
+
+	// FunctionRequest is the body a function is invoked with.
+	FunctionRequest struct {
+		Data Data `json:"data"`
+	}
+
+	// FunctionError is returned by InvokeFunction when the invoked function failed.
+	FunctionError struct {
+		Name       string
+		StatusCode int
+		Response   Response
+		Body       []byte
+	}
+)
+
+func (o *object) Functions() FunctionApis {
+	return o
+}
+
+func (e *FunctionError) Error() string {
+	message := e.Response.Error
+	if message == "" && e.Response.Data != nil {
+		message = fmt.Sprint(e.Response.Data["error"])
+	}
+	if message == "" {
+		message = string(e.Body)
+	}
+
+	return fmt.Sprintf("function %s failed with status %d: %s", e.Name, e.StatusCode, message)
+}
+
+// WithCorrelationId returns a copy of ctx carrying the correlation id sent to invoked functions.
+func WithCorrelationId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIdKey{}, id)
+}
+
+// CorrelationId returns the correlation id of ctx or an empty string.
+func CorrelationId(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIdKey{}).(string)
+	return id
+}
+
+/*
+CorrelationContext returns the context of an incoming function request carrying its correlation id,
+so the functions invoked while handling it share the id of the caller.
+*/
+func CorrelationContext(r *http.Request) context.Context {
+	id := r.Header.Get(CorrelationIdHeader)
+	if id == "" {
+		id = NewGUID()
+	}
+
+	return WithCorrelationId(r.Context(), id)
+}
+
+func (o *object) InvokeFunction(ctx context.Context, name string, payload Data) (Response, error) {
+	var (
+		response Response
+		url      = fmt.Sprintf("%s/function/%s", o.config.functionsURL(), name)
+	)
+
+	payload = o.functionPayload(payload)
+
+	statusCode, respByte, err := o.send(ctx, url, http.MethodPost, FunctionRequest{Data: payload}, o.functionHeaders(ctx, payload.AppId))
+	if err != nil {
+		return Response{}, err
+	}
+
+	if err = json.Unmarshal(respByte, &response); err != nil && statusCode < http.StatusBadRequest {
+		return Response{}, fmt.Errorf("function %s: error while unmarshalling response: %w", name, err)
+	}
+
+	if statusCode >= http.StatusBadRequest || response.Status == "error" {
+		return response, &FunctionError{Name: name, StatusCode: statusCode, Response: response, Body: respByte}
+	}
+
+	return response, nil
+}
+
+func (o *object) InvokeFunctionAsync(ctx context.Context, name string, payload Data) error {
+	url := fmt.Sprintf("%s/async-function/%s", o.config.functionsURL(), name)
+
+	payload = o.functionPayload(payload)
+
+	statusCode, respByte, err := o.send(ctx, url, http.MethodPost, FunctionRequest{Data: payload}, o.functionHeaders(ctx, payload.AppId))
+	if err != nil {
+		return err
+	}
+
+	if statusCode >= http.StatusBadRequest {
+		return &FunctionError{Name: name, StatusCode: statusCode, Body: respByte}
+	}
+
+	return nil
+}
+
+func (o *object) functionPayload(payload Data) Data {
+	if payload.AppId == "" {
+		payload.AppId = o.config.AppId
+	}
+
+	return payload
+}
+
+func (o *object) functionHeaders(ctx context.Context, appId string) map[string]string {
+	correlationId := CorrelationId(ctx)
+	if correlationId == "" {
+		correlationId = NewGUID()
+	}
+
+	header := map[string]string{
+		"authorization":     "API-KEY",
+		"X-API-KEY":         appId,
+		"Content-Type":      "application/json",
+		CorrelationIdHeader: correlationId,
+	}
+	if o.config.FunctionName != "" {
+		header["X-Caller-Function"] = o.config.FunctionName
+	}
+
+	return header
+}
@@ -0,0 +1,62 @@
+package ucodesdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvokeFunction(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	server.HandleFunction("sum", func(ctx context.Context, data ucodesdk.Data) (ucodesdk.Response, error) {
+		if data.ObjectData["a"] == nil {
+			return ucodesdk.Response{}, errors.New("a is required")
+		}
+		return ucodesdk.Response{Data: map[string]interface{}{"correlation_id": ucodesdk.CorrelationId(ctx)}}, nil
+	})
+
+	var (
+		ucodeApi = server.Client("caller_app_id")
+		ctx      = ucodesdk.WithCorrelationId(context.Background(), "correlation-1")
+	)
+	ucodeApi.Config().FunctionName = "caller"
+
+	response, err := ucodeApi.Functions().InvokeFunction(ctx, "sum", ucodesdk.Data{ObjectData: map[string]interface{}{"a": 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, "done", response.Status)
+	assert.Equal(t, "correlation-1", response.Data["correlation_id"])
+
+	invocations := server.Invocations("sum")
+	if assert.Len(t, invocations, 1) {
+		assert.Equal(t, "caller_app_id", invocations[0].Data.AppId)
+		assert.Equal(t, "caller", invocations[0].Caller)
+	}
+
+	// function errors are returned as *FunctionError
+	_, err = ucodeApi.Functions().InvokeFunction(ctx, "sum", ucodesdk.Data{})
+	var functionErr *ucodesdk.FunctionError
+	if assert.ErrorAs(t, err, &functionErr) {
+		assert.Equal(t, 500, functionErr.StatusCode)
+		assert.Contains(t, err.Error(), "a is required")
+	}
+
+	_, err = ucodeApi.Functions().InvokeFunction(ctx, "missing", ucodesdk.Data{})
+	assert.ErrorAs(t, err, &functionErr)
+
+	// async invocations get a correlation id even without one in ctx
+	err = ucodeApi.Functions().InvokeFunctionAsync(context.Background(), "sum", ucodesdk.Data{AppId: "other_app_id", ObjectData: map[string]interface{}{"a": 1}})
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(server.Invocations("sum")) == 3 }, time.Second, 10*time.Millisecond)
+	last := server.Invocations("sum")[2]
+	assert.True(t, last.Async)
+	assert.Equal(t, "other_app_id", last.Data.AppId)
+	assert.NotEmpty(t, last.CorrelationId)
+}
@@ -0,0 +1,230 @@
+package ucodesdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	defaultLoaderWait     = 2 * time.Millisecond
+	defaultLoaderMaxBatch = 100
+)
+
+// ErrNotFound is returned by Loader.Load when the table has no object with the guid.
+var ErrNotFound = errors.New("object not found")
+
+type (
+	// LoaderOptions configures a Loader. A nil *LoaderOptions uses the defaults.
+	LoaderOptions struct {
+		AppId       string
+		DisableFaas bool
+		// Wait is how long guids of a table are collected before they are loaded, 2ms by default.
+		// When negative, batches are only sent when they are full or on Flush.
+		Wait time.Duration
+		// MaxBatch is the number of guids loaded with one request, 100 by default.
+		MaxBatch int
+	}
+
+	/*
+		Loader batches lookups of objects by guid: every guid requested within LoaderOptions.Wait
+		is loaded with a single GetListSlim per table, instead of one GetSingleSlim per guid.
+
+		Loaded objects are kept for the life of the Loader, so create one per invocation
+		(e.g. in the handler) and do not share it between requests.
+	*/
+	Loader struct {
+		ucodeApi UcodeApis
+		options  LoaderOptions
+
+		mu      sync.Mutex
+		results map[string]*loadResult
+		pending map[string]*loadBatch
+	}
+
+	loadResult struct {
+		done   chan struct{}
+		object map[string]interface{}
+		err    error
+	}
+
+	loadBatch struct {
+		guids []string
+		timer *time.Timer
+	}
+)
+
+func NewLoader(ucodeApi UcodeApis, opts *LoaderOptions) *Loader {
+	var options LoaderOptions
+	if opts != nil {
+		options = *opts
+	}
+	if options.Wait == 0 {
+		options.Wait = defaultLoaderWait
+	}
+	if options.MaxBatch <= 0 {
+		options.MaxBatch = defaultLoaderMaxBatch
+	}
+
+	return &Loader{
+		ucodeApi: ucodeApi,
+		options:  options,
+		results:  map[string]*loadResult{},
+		pending:  map[string]*loadBatch{},
+	}
+}
+
+// Load returns the object of the table with the guid, or ErrNotFound. The returned map is a copy.
+func (l *Loader) Load(ctx context.Context, tableSlug, guid string) (map[string]interface{}, error) {
+	result := l.enqueue(tableSlug, guid)
+
+	select {
+	case <-result.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	object := make(map[string]interface{}, len(result.object))
+	for key, value := range result.object {
+		object[key] = value
+	}
+
+	return object, nil
+}
+
+// LoadMany returns the objects of the table with the guids in the same order, nil for the guids which are not found.
+func (l *Loader) LoadMany(ctx context.Context, tableSlug string, guids []string) ([]map[string]interface{}, error) {
+	// every guid is queued before waiting, so they go out in the same batch
+	for _, guid := range guids {
+		l.enqueue(tableSlug, guid)
+	}
+
+	objects := make([]map[string]interface{}, len(guids))
+	for i, guid := range guids {
+		object, err := l.Load(ctx, tableSlug, guid)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		objects[i] = object
+	}
+
+	return objects, nil
+}
+
+// Flush loads the queued guids of every table now and returns when they are loaded.
+func (l *Loader) Flush() {
+	l.mu.Lock()
+	tables := make([]string, 0, len(l.pending))
+	for tableSlug := range l.pending {
+		tables = append(tables, tableSlug)
+	}
+	l.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, tableSlug := range tables {
+		wg.Add(1)
+		go func(tableSlug string) {
+			defer wg.Done()
+			l.dispatch(tableSlug)
+		}(tableSlug)
+	}
+	wg.Wait()
+}
+
+// enqueue returns the result of the guid, queueing it when it is neither loaded nor queued.
+func (l *Loader) enqueue(tableSlug, guid string) *loadResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := tableSlug + "\x00" + guid
+	if result, ok := l.results[key]; ok {
+		return result
+	}
+
+	result := &loadResult{done: make(chan struct{})}
+	l.results[key] = result
+
+	batch, ok := l.pending[tableSlug]
+	if !ok {
+		batch = &loadBatch{}
+		l.pending[tableSlug] = batch
+		if l.options.Wait > 0 {
+			batch.timer = time.AfterFunc(l.options.Wait, func() { l.dispatch(tableSlug) })
+		}
+	}
+	batch.guids = append(batch.guids, guid)
+
+	if len(batch.guids) >= l.options.MaxBatch {
+		delete(l.pending, tableSlug)
+		if batch.timer != nil {
+			batch.timer.Stop()
+		}
+		go l.load(tableSlug, batch.guids)
+	}
+
+	return result
+}
+
+// dispatch loads the queued guids of the table, if any.
+func (l *Loader) dispatch(tableSlug string) {
+	l.mu.Lock()
+	batch, ok := l.pending[tableSlug]
+	delete(l.pending, tableSlug)
+	l.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	if batch.timer != nil {
+		batch.timer.Stop()
+	}
+
+	l.load(tableSlug, batch.guids)
+}
+
+// load gets the objects with one GetListSlim and completes their results. Failed results are forgotten, so they can be loaded again.
+func (l *Loader) load(tableSlug string, guids []string) {
+	list, _, err := l.ucodeApi.GetListSlim(&ArgumentWithPegination{
+		AppId:       l.options.AppId,
+		TableSlug:   tableSlug,
+		Request:     Request{Data: map[string]interface{}{"guid": guids}},
+		DisableFaas: l.options.DisableFaas,
+		Limit:       len(guids),
+		Page:        1,
+	})
+
+	objects := map[string]map[string]interface{}{}
+	if err == nil {
+		for _, object := range list.Data.Data.Response {
+			objects[cast.ToString(object["guid"])] = object
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, guid := range guids {
+		key := tableSlug + "\x00" + guid
+		result := l.results[key]
+
+		switch object, ok := objects[guid]; {
+		case err != nil:
+			result.err = fmt.Errorf("loading %s: %w", tableSlug, err)
+			delete(l.results, key)
+		case !ok:
+			result.err = fmt.Errorf("%s %s: %w", tableSlug, guid, ErrNotFound)
+		default:
+			result.object = object
+		}
+
+		close(result.done)
+	}
+}
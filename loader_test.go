@@ -0,0 +1,100 @@
+package ucodesdk_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId)
+		ctx      = context.Background()
+		rooms    = make([]map[string]interface{}, 50)
+	)
+	for i := range rooms {
+		rooms[i] = map[string]interface{}{"guid": fmt.Sprintf("room-%d", i), "name": fmt.Sprintf("room %d", i)}
+	}
+	server.Seed(appId, "room", rooms...)
+
+	listRequests := func() int {
+		var count int
+		for _, request := range server.Requests() {
+			if strings.Contains(request, "get-list/room") {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("one request per batch", func(t *testing.T) {
+		var (
+			loader = ucodesdk.NewLoader(ucodeApi, &ucodesdk.LoaderOptions{Wait: 20 * time.Millisecond, MaxBatch: 40})
+			wg     sync.WaitGroup
+			before = listRequests()
+		)
+
+		// every house resolves its room, 60 lookups of 50 rooms
+		for i := 0; i < 60; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				room, err := loader.Load(ctx, "room", fmt.Sprintf("room-%d", i%50))
+				if assert.NoError(t, err) {
+					assert.Equal(t, fmt.Sprintf("room %d", i%50), room["name"])
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		// a full batch of 40 and the remaining 10 after the wait
+		assert.Equal(t, before+2, listRequests())
+
+		// loaded objects are kept by the loader
+		_, err := loader.Load(ctx, "room", "room-7")
+		assert.NoError(t, err)
+		assert.Equal(t, before+2, listRequests())
+	})
+
+	t.Run("load many and flush", func(t *testing.T) {
+		var (
+			loader = ucodesdk.NewLoader(ucodeApi, &ucodesdk.LoaderOptions{Wait: -1})
+			before = listRequests()
+			done   = make(chan struct{})
+		)
+
+		go func() {
+			defer close(done)
+			objects, err := loader.LoadMany(ctx, "room", []string{"room-1", "missing", "room-2"})
+			assert.NoError(t, err)
+			assert.Equal(t, "room 1", objects[0]["name"])
+			assert.Nil(t, objects[1])
+			assert.Equal(t, "room 2", objects[2]["name"])
+		}()
+
+		assert.Eventually(t, func() bool {
+			loader.Flush()
+			select {
+			case <-done:
+				return true
+			default:
+				return false
+			}
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, before+1, listRequests())
+
+		_, err := loader.Load(ctx, "room", "missing")
+		assert.ErrorIs(t, err, ucodesdk.ErrNotFound)
+	})
+}
@@ -0,0 +1,252 @@
+/*
+Package migrate copies tables from one uCode app to another, e.g. from a
+Mongo app to a Postgres app or from staging to production.
+
+Objects keep their guids, so relation and many-to-many (<table_to>_ids)
+fields stay valid once every table is copied. Objects whose guid already
+exists in the target are conflicts, resolved by Options.Conflict.
+*/
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/exporter"
+	"github.com/spf13/cast"
+)
+
+// Conflict strategies, applied to objects whose guid exists in the target.
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictFail      = "fail"
+)
+
+const defaultBatchSize = 100
+
+// ErrConflict is returned with ConflictFail when an object already exists in the target.
+var ErrConflict = errors.New("object already exists in the target")
+
+type (
+	Options struct {
+		// SourceAppId and TargetAppId default to the app ids of the source and target client configs.
+		SourceAppId string
+		TargetAppId string
+		DisableFaas bool
+		// Tables are copied in order, list referenced tables first.
+		Tables []string
+		// BatchSize is the number of objects read and written at once, 100 by default.
+		BatchSize int
+		// Conflict is skip (default), overwrite or fail.
+		Conflict string
+		// DryRun reads both apps and reports what would be written without writing.
+		DryRun bool
+		// Verify runs Verify after copying. It is skipped on dry runs, nothing was written to compare.
+		Verify bool
+		// IgnoreFields are left out of the checksums, created_at and updated_at by default.
+		IgnoreFields []string
+	}
+
+	Report struct {
+		Tables []TableReport
+	}
+
+	TableReport struct {
+		TableSlug string
+		// Read is the number of source objects, the others count what was or, in a dry run, would be done.
+		Read    int
+		Created int
+		Updated int
+		Skipped int
+		// Verification is set when Options.Verify is.
+		Verification *Verification
+	}
+)
+
+// Verified reports whether every table was verified and matched.
+func (r Report) Verified() bool {
+	for _, table := range r.Tables {
+		if table.Verification == nil || !table.Verification.OK() {
+			return false
+		}
+	}
+
+	return len(r.Tables) > 0
+}
+
+/*
+Migrate copies the tables from the source app to the target app.
+
+Source and target may be the same client when both apps are on the same
+uCode instance. Every batch of source objects is looked up in the target by
+guid first: missing objects are created with CreateMany, existing ones are
+skipped, overwritten with UpdateMany or fail the migration before the batch
+is written.
+*/
+func Migrate(ctx context.Context, source, target ucodesdk.UcodeApis, opts Options) (Report, error) {
+	var report Report
+
+	opts, err := opts.withDefaults(source, target)
+	if err != nil {
+		return report, err
+	}
+
+	for _, tableSlug := range opts.Tables {
+		table, err := migrateTable(ctx, source, target, tableSlug, opts)
+		report.Tables = append(report.Tables, table)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if !opts.Verify || opts.DryRun {
+		return report, nil
+	}
+
+	verifications, err := Verify(ctx, source, target, opts)
+	for i := range verifications {
+		report.Tables[i].Verification = &verifications[i]
+	}
+
+	return report, err
+}
+
+func (opts Options) withDefaults(source, target ucodesdk.UcodeApis) (Options, error) {
+	if opts.SourceAppId == "" {
+		opts.SourceAppId = source.Config().AppId
+	}
+	if opts.TargetAppId == "" {
+		opts.TargetAppId = target.Config().AppId
+	}
+	if opts.SourceAppId == opts.TargetAppId && source.Config().BaseURL == target.Config().BaseURL {
+		return opts, errors.New("source and target are the same app")
+	}
+
+	if len(opts.Tables) == 0 {
+		return opts, errors.New("no tables to migrate")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+
+	switch opts.Conflict {
+	case "":
+		opts.Conflict = ConflictSkip
+	case ConflictSkip, ConflictOverwrite, ConflictFail:
+	default:
+		return opts, fmt.Errorf("unknown conflict strategy %q, expected %s, %s or %s", opts.Conflict, ConflictSkip, ConflictOverwrite, ConflictFail)
+	}
+
+	if opts.IgnoreFields == nil {
+		opts.IgnoreFields = []string{"created_at", "updated_at"}
+	}
+
+	return opts, nil
+}
+
+func migrateTable(ctx context.Context, source, target ucodesdk.UcodeApis, tableSlug string, opts Options) (TableReport, error) {
+	var (
+		report      = TableReport{TableSlug: tableSlug}
+		bulkOptions = &ucodesdk.BulkOptions{
+			AppId:       opts.TargetAppId,
+			DisableFaas: opts.DisableFaas,
+			ChunkSize:   opts.BatchSize,
+			StopOnError: true,
+		}
+	)
+
+	err := exporter.Walk(ctx, source, exporter.Options{
+		AppId:       opts.SourceAppId,
+		TableSlug:   tableSlug,
+		DisableFaas: opts.DisableFaas,
+		PageSize:    opts.BatchSize,
+	}, func(page []map[string]interface{}) error {
+		report.Read += len(page)
+
+		existing, err := existingGuids(ctx, target, tableSlug, page, opts)
+		if err != nil {
+			return err
+		}
+
+		var create, update []map[string]interface{}
+		for _, object := range page {
+			guid := cast.ToString(object["guid"])
+			if !existing[guid] {
+				create = append(create, object)
+				continue
+			}
+
+			switch opts.Conflict {
+			case ConflictFail:
+				return fmt.Errorf("%s %s: %w", tableSlug, guid, ErrConflict)
+			case ConflictOverwrite:
+				update = append(update, object)
+			default:
+				report.Skipped++
+			}
+		}
+
+		if opts.DryRun {
+			report.Created += len(create)
+			report.Updated += len(update)
+			return nil
+		}
+
+		if len(create) > 0 {
+			results, err := target.Bulk().CreateMany(ctx, tableSlug, create, bulkOptions)
+			report.Created += len(results) - len(results.Failed())
+			if err != nil {
+				return fmt.Errorf("creating %s: %w", tableSlug, err)
+			}
+		}
+
+		if len(update) > 0 {
+			results, err := target.Bulk().UpdateMany(ctx, tableSlug, update, bulkOptions)
+			report.Updated += len(results) - len(results.Failed())
+			if err != nil {
+				return fmt.Errorf("updating %s: %w", tableSlug, err)
+			}
+		}
+
+		return nil
+	})
+
+	return report, err
+}
+
+// existingGuids returns which guids of the page already exist in the target table.
+func existingGuids(ctx context.Context, target ucodesdk.UcodeApis, tableSlug string, page []map[string]interface{}, opts Options) (map[string]bool, error) {
+	guids := make([]string, 0, len(page))
+	for _, object := range page {
+		if guid := cast.ToString(object["guid"]); guid != "" {
+			guids = append(guids, guid)
+		}
+	}
+
+	existing := map[string]bool{}
+	if len(guids) == 0 {
+		return existing, ctx.Err()
+	}
+
+	// an array filter matches objects with any of its values, see "GetListSlim by guids" of TestEndToEnd
+	list, _, err := target.GetListSlim(&ucodesdk.ArgumentWithPegination{
+		AppId:       opts.TargetAppId,
+		TableSlug:   tableSlug,
+		Request:     ucodesdk.Request{Data: map[string]interface{}{"guid": guids}},
+		DisableFaas: opts.DisableFaas,
+		Limit:       len(guids),
+		Page:        1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s in the target: %w", tableSlug, err)
+	}
+
+	for _, object := range list.Data.Data.Response {
+		existing[cast.ToString(object["guid"])] = true
+	}
+
+	return existing, nil
+}
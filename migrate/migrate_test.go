@@ -0,0 +1,91 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		mongoAppId    = "mongo_app_id"
+		postgresAppId = "postgres_app_id"
+		ucodeApi      = server.Client(mongoAppId)
+		ctx           = context.Background()
+	)
+	for i := 0; i < 5; i++ {
+		server.Seed(mongoAppId, "room", map[string]interface{}{"guid": fmt.Sprintf("room-%d", i), "name": fmt.Sprintf("room %d", i)})
+		server.Seed(mongoAppId, "houses", map[string]interface{}{
+			"guid":     fmt.Sprintf("house-%d", i),
+			"name":     fmt.Sprintf("house %d", i),
+			"price":    15000 + i,
+			"room_id":  fmt.Sprintf("room-%d", i),
+			"room_ids": []string{fmt.Sprintf("room-%d", i)},
+		})
+	}
+	// already migrated, with another name
+	server.Seed(postgresAppId, "houses", map[string]interface{}{"guid": "house-0", "name": "old", "price": 15000, "room_id": "room-0", "room_ids": []string{"room-0"}})
+
+	opts := Options{TargetAppId: postgresAppId, Tables: []string{"room", "houses"}, BatchSize: 2, Verify: true}
+
+	t.Run("dry run", func(t *testing.T) {
+		dryRun := opts
+		dryRun.DryRun = true
+
+		report, err := Migrate(ctx, ucodeApi, ucodeApi, dryRun)
+		assert.NoError(t, err)
+		assert.Equal(t, TableReport{TableSlug: "houses", Read: 5, Created: 4, Skipped: 1}, report.Tables[1])
+		assert.False(t, report.Verified())
+		assert.Len(t, server.Objects(postgresAppId, "houses"), 1)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		fail := opts
+		fail.Conflict = ConflictFail
+
+		report, err := Migrate(ctx, ucodeApi, ucodeApi, fail)
+		assert.ErrorIs(t, err, ErrConflict)
+		// rooms are copied before the first house conflicts
+		assert.Equal(t, 5, report.Tables[0].Created)
+		assert.Len(t, server.Objects(postgresAppId, "houses"), 1)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		report, err := Migrate(ctx, ucodeApi, ucodeApi, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, TableReport{TableSlug: "room", Read: 5, Skipped: 5}, withoutVerification(report.Tables[0]))
+		assert.Equal(t, TableReport{TableSlug: "houses", Read: 5, Created: 4, Skipped: 1}, withoutVerification(report.Tables[1]))
+
+		houses := report.Tables[1].Verification
+		assert.Equal(t, 5, houses.TargetCount)
+		assert.Equal(t, 1, houses.Different)
+		assert.False(t, report.Verified())
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		overwrite := opts
+		overwrite.Conflict = ConflictOverwrite
+
+		report, err := Migrate(ctx, ucodeApi, ucodeApi, overwrite)
+		assert.NoError(t, err)
+		assert.Equal(t, TableReport{TableSlug: "houses", Read: 5, Updated: 5}, withoutVerification(report.Tables[1]))
+		assert.True(t, report.Verified(), report.Tables[1].Verification)
+		assert.Equal(t, "house 0", server.Objects(postgresAppId, "houses")[0]["name"])
+	})
+
+	t.Run("same app", func(t *testing.T) {
+		_, err := Migrate(ctx, ucodeApi, ucodeApi, Options{Tables: []string{"houses"}})
+		assert.Error(t, err)
+	})
+}
+
+func withoutVerification(report TableReport) TableReport {
+	report.Verification = nil
+	return report
+}
@@ -0,0 +1,139 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/exporter"
+	"github.com/spf13/cast"
+)
+
+// Verification compares a table of the source app with the same table of the target app.
+type Verification struct {
+	TableSlug   string
+	SourceCount int
+	TargetCount int
+	// Checksums are computed over the objects ordered by guid, see Verify.
+	SourceChecksum string
+	TargetChecksum string
+	// Missing is the number of source objects not in the target.
+	Missing int
+	// Different is the number of source objects whose target copy has other values.
+	Different int
+}
+
+// OK reports whether the target table holds exactly the source objects.
+func (v Verification) OK() bool {
+	return v.SourceCount == v.TargetCount && v.SourceChecksum == v.TargetChecksum
+}
+
+/*
+Verify compares the row counts and checksums of opts.Tables in both apps.
+
+The checksum of a table is the SHA-256 of the objects ordered by guid, each
+object hashed as JSON with sorted keys, without opts.IgnoreFields and
+without null fields, since backends differ in returning unset fields.
+*/
+func Verify(ctx context.Context, source, target ucodesdk.UcodeApis, opts Options) ([]Verification, error) {
+	opts, err := opts.withDefaults(source, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifications []Verification
+	for _, tableSlug := range opts.Tables {
+		sourceHashes, err := hashTable(ctx, source, opts.SourceAppId, tableSlug, opts)
+		if err != nil {
+			return verifications, err
+		}
+
+		targetHashes, err := hashTable(ctx, target, opts.TargetAppId, tableSlug, opts)
+		if err != nil {
+			return verifications, err
+		}
+
+		verification := Verification{
+			TableSlug:      tableSlug,
+			SourceCount:    len(sourceHashes),
+			TargetCount:    len(targetHashes),
+			SourceChecksum: checksum(sourceHashes),
+			TargetChecksum: checksum(targetHashes),
+		}
+		for guid, hash := range sourceHashes {
+			targetHash, ok := targetHashes[guid]
+			switch {
+			case !ok:
+				verification.Missing++
+			case targetHash != hash:
+				verification.Different++
+			}
+		}
+
+		verifications = append(verifications, verification)
+	}
+
+	return verifications, nil
+}
+
+// hashTable returns the hash of every object of the table by guid.
+func hashTable(ctx context.Context, ucodeApi ucodesdk.UcodeApis, appId, tableSlug string, opts Options) (map[string]string, error) {
+	hashes := map[string]string{}
+
+	err := exporter.Walk(ctx, ucodeApi, exporter.Options{
+		AppId:       appId,
+		TableSlug:   tableSlug,
+		DisableFaas: opts.DisableFaas,
+		PageSize:    opts.BatchSize,
+	}, func(page []map[string]interface{}) error {
+		for _, object := range page {
+			hash, err := hashObject(object, opts.IgnoreFields)
+			if err != nil {
+				return err
+			}
+			hashes[cast.ToString(object["guid"])] = hash
+		}
+		return nil
+	})
+
+	return hashes, err
+}
+
+func hashObject(object map[string]interface{}, ignore []string) (string, error) {
+	canonical := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		if value != nil {
+			canonical[key] = value
+		}
+	}
+	for _, field := range ignore {
+		delete(canonical, field)
+	}
+
+	// maps are marshaled with sorted keys
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func checksum(hashes map[string]string) string {
+	guids := make([]string, 0, len(hashes))
+	for guid := range hashes {
+		guids = append(guids, guid)
+	}
+	sort.Strings(guids)
+
+	sum := sha256.New()
+	for _, guid := range guids {
+		sum.Write([]byte(guid + ":" + hashes[guid] + "\n"))
+	}
+
+	return hex.EncodeToString(sum.Sum(nil))
+}
@@ -11,6 +11,10 @@ type (
 		TableSlug   string  `json:"table_slug"`
 		Request     Request `json:"request"`
 		DisableFaas bool    `json:"disable_faas"`
+		// IdempotencyKey is sent by CreateObject, UpdateObject and MultipleUpdate, a new one per call when empty.
+		IdempotencyKey string `json:"-"`
+		// DisableCoalescing sends a read even when an identical one is in flight.
+		DisableCoalescing bool `json:"-"`
 	}
 
 	ArgumentWithPegination struct {
@@ -20,6 +24,8 @@ type (
 		DisableFaas bool    `json:"disable_faas"`
 		Limit       int     `json:"limit"`
 		Page        int     `json:"page"`
+		// DisableCoalescing sends a read even when an identical one is in flight.
+		DisableCoalescing bool `json:"-"`
 	}
 
 	Data struct {
@@ -65,6 +71,9 @@ type (
 	// GetListClientApiResponse This is get list api response >>>>> GET_LIST, GET_LIST_SLIM
 	GetListClientApiResponse struct {
 		Data GetListClientApiData `json:"data"`
+
+		// page is set by GetList and GetListSlim, see PageInfo
+		page PageInfo
 	}
 
 	GetListClientApiData struct {
@@ -73,6 +82,8 @@ type (
 
 	GetListClientApiResp struct {
 		Response []map[string]interface{} `json:"response"`
+		// Count is the number of objects matching the filter, nil when the server does not send it.
+		Count *int `json:"count,omitempty"`
 	}
 	// GetListAggregationClientApiResponse  This is get list aggregation response
 	GetListAggregationClientApiResponse struct {
@@ -0,0 +1,414 @@
+package ucodesdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// ErrMissingField is returned by the strict accessors of Object when the field is missing or null.
+var ErrMissingField = errors.New("field is missing")
+
+/*
+Object is an object of a table as returned by the API, with typed accessors.
+
+The accessors convert values with spf13/cast and return the zero value when the
+field is missing or can not be converted. Their E variants (StringE, IntE, ...)
+return an error instead, wrapping ErrMissingField for missing and null fields.
+*/
+type Object map[string]interface{}
+
+// GUID returns the guid of the object.
+func (o Object) GUID() string {
+	return o.String("guid")
+}
+
+// Has reports whether the field is set and not null.
+func (o Object) Has(field string) bool {
+	return o[field] != nil
+}
+
+func (o Object) String(field string) string {
+	value, _ := o.StringE(field)
+	return value
+}
+
+func (o Object) StringE(field string) (string, error) {
+	value, err := o.value(field)
+	if err != nil {
+		return "", err
+	}
+
+	return convert(field, value, cast.ToStringE)
+}
+
+func (o Object) Int(field string) int {
+	value, _ := o.IntE(field)
+	return value
+}
+
+func (o Object) IntE(field string) (int, error) {
+	value, err := o.value(field)
+	if err != nil {
+		return 0, err
+	}
+
+	return convert(field, value, toInt)
+}
+
+func (o Object) Int64(field string) int64 {
+	value, _ := o.Int64E(field)
+	return value
+}
+
+func (o Object) Int64E(field string) (int64, error) {
+	value, err := o.value(field)
+	if err != nil {
+		return 0, err
+	}
+
+	return convert(field, value, toInt64)
+}
+
+func (o Object) Float(field string) float64 {
+	value, _ := o.FloatE(field)
+	return value
+}
+
+func (o Object) FloatE(field string) (float64, error) {
+	value, err := o.value(field)
+	if err != nil {
+		return 0, err
+	}
+
+	return convert(field, value, cast.ToFloat64E)
+}
+
+func (o Object) Bool(field string) bool {
+	value, _ := o.BoolE(field)
+	return value
+}
+
+func (o Object) BoolE(field string) (bool, error) {
+	value, err := o.value(field)
+	if err != nil {
+		return false, err
+	}
+
+	return convert(field, value, cast.ToBoolE)
+}
+
+// Time parses dates and datetimes in the formats understood by cast.ToTime, in UTC when they have no zone.
+func (o Object) Time(field string) time.Time {
+	value, _ := o.TimeE(field)
+	return value
+}
+
+func (o Object) TimeE(field string) (time.Time, error) {
+	value, err := o.value(field)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return convert(field, value, cast.ToTimeE)
+}
+
+// Decimal returns a number or numeric string exactly, e.g. for prices. It is never nil.
+func (o Object) Decimal(field string) *big.Rat {
+	value, err := o.DecimalE(field)
+	if err != nil {
+		return new(big.Rat)
+	}
+
+	return value
+}
+
+func (o Object) DecimalE(field string) (*big.Rat, error) {
+	value, err := o.StringE(field)
+	if err != nil {
+		return nil, err
+	}
+
+	decimal, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("field %q: %q is not a decimal", field, value)
+	}
+
+	return decimal, nil
+}
+
+// Strings returns a list field, like the guids of a many-to-many field. A single string is returned as a list of one.
+func (o Object) Strings(field string) []string {
+	value, _ := o.StringsE(field)
+	return value
+}
+
+func (o Object) StringsE(field string) ([]string, error) {
+	value, err := o.value(field)
+	if err != nil {
+		return nil, err
+	}
+
+	// cast splits strings on spaces
+	if s, ok := value.(string); ok {
+		return []string{s}, nil
+	}
+
+	return convert(field, value, cast.ToStringSliceE)
+}
+
+/*
+Object returns the related object of an expanded relation (see with_relations),
+read from the relation field or, when that holds the guid, from <relation>_id_data.
+*/
+func (o Object) Object(relation string) Object {
+	value, _ := o.ObjectE(relation)
+	return value
+}
+
+func (o Object) ObjectE(relation string) (Object, error) {
+	value, ok := o[relation].(map[string]interface{})
+	if !ok {
+		value, ok = o[relation+"_id_data"].(map[string]interface{})
+	}
+	if ok {
+		return value, nil
+	}
+
+	if o[relation] == nil && o[relation+"_id_data"] == nil {
+		return nil, fmt.Errorf("field %q: %w", relation, ErrMissingField)
+	}
+
+	return nil, fmt.Errorf("field %q: %T is not an object", relation, o[relation])
+}
+
+// Objects returns the objects of a list field, like expanded many-to-many relations.
+func (o Object) Objects(relation string) []Object {
+	value, _ := o.ObjectsE(relation)
+	return value
+}
+
+func (o Object) ObjectsE(relation string) ([]Object, error) {
+	value, err := o.value(relation)
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		if maps, ok := value.([]map[string]interface{}); ok {
+			return toObjects(maps), nil
+		}
+		return nil, fmt.Errorf("field %q: %T is not a list of objects", relation, value)
+	}
+
+	objects := make([]Object, len(items))
+	for i, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("field %q: item %d is %T, not an object", relation, i, item)
+		}
+		objects[i] = object
+	}
+
+	return objects, nil
+}
+
+/*
+Decode stores the object in the struct pointed to by out, like json.Unmarshal.
+An expanded relation is decoded by tagging a struct field with the name of its data:
+
+	type House struct {
+		Guid string `json:"guid"`
+		Room *Room  `json:"room_id_data"`
+	}
+*/
+func (o Object) Decode(out interface{}) error {
+	objectByte, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(objectByte, out)
+}
+
+func (o Object) value(field string) (interface{}, error) {
+	value := o[field]
+	if value == nil {
+		return nil, fmt.Errorf("field %q: %w", field, ErrMissingField)
+	}
+
+	return value, nil
+}
+
+func convert[T any](field string, value interface{}, to func(interface{}) (T, error)) (T, error) {
+	result, err := to(value)
+	if err != nil {
+		return result, fmt.Errorf("field %q: %w", field, err)
+	}
+
+	return result, nil
+}
+
+// toInt is cast.ToIntE, except strings are parsed as decimal numbers: "010" is 10, not 8.
+func toInt(value interface{}) (int, error) {
+	if s, ok := value.(string); ok {
+		return strconv.Atoi(decimalString(s))
+	}
+
+	return cast.ToIntE(value)
+}
+
+// toInt64 is cast.ToInt64E, except strings are parsed as decimal numbers like toInt.
+func toInt64(value interface{}) (int64, error) {
+	if s, ok := value.(string); ok {
+		return strconv.ParseInt(decimalString(s), 10, 64)
+	}
+
+	return cast.ToInt64E(value)
+}
+
+// decimalString trims the spaces and a zero fraction ("5.00") of a number like cast does.
+func decimalString(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '.'); i >= 0 && i < len(s)-1 && strings.Trim(s[i+1:], "0") == "" {
+		s = s[:i]
+	}
+
+	return s
+}
+
+func toObjects(maps []map[string]interface{}) []Object {
+	if maps == nil {
+		return nil
+	}
+
+	objects := make([]Object, len(maps))
+	for i, object := range maps {
+		objects[i] = object
+	}
+
+	return objects
+}
+
+/*
+Every response type has Item, Items and Count, whatever its nesting:
+Item is the single object of the response, or the first one of a list,
+Items the objects of a list, or the single object as a list of one,
+and Count the number of objects in the response.
+*/
+
+// Item returns the created object.
+func (d Datas) Item() Object {
+	return d.Data.Data.Data
+}
+
+func (d Datas) Items() []Object {
+	return single(d.Item())
+}
+
+func (d Datas) Count() int {
+	return len(d.Items())
+}
+
+// Item returns the object.
+func (r ClientApiResponse) Item() Object {
+	return r.Data.Data.Response
+}
+
+func (r ClientApiResponse) Items() []Object {
+	return single(r.Item())
+}
+
+func (r ClientApiResponse) Count() int {
+	return len(r.Items())
+}
+
+// Items returns the objects of the page.
+func (r GetListClientApiResponse) Items() []Object {
+	return toObjects(r.Data.Data.Response)
+}
+
+func (r GetListClientApiResponse) Item() Object {
+	return first(r.Items())
+}
+
+// Count returns the number of objects in the page.
+func (r GetListClientApiResponse) Count() int {
+	return len(r.Data.Data.Response)
+}
+
+// Total returns the number of objects matching the filter, when the server sent it.
+func (r GetListClientApiResponse) Total() (int, bool) {
+	if r.Data.Data.Count == nil {
+		return 0, false
+	}
+
+	return *r.Data.Data.Count, true
+}
+
+// PageInfo describes the page when the response was returned by GetList or GetListSlim.
+func (r GetListClientApiResponse) PageInfo() PageInfo {
+	return r.page
+}
+
+// Items returns the result rows of the pipeline.
+func (r GetListAggregationClientApiResponse) Items() []Object {
+	return toObjects(r.Data.Data.Data)
+}
+
+func (r GetListAggregationClientApiResponse) Item() Object {
+	return first(r.Items())
+}
+
+func (r GetListAggregationClientApiResponse) Count() int {
+	return len(r.Data.Data.Data)
+}
+
+// Item returns the updated object.
+func (r ClientApiUpdateResponse) Item() Object {
+	return r.Data.Data
+}
+
+func (r ClientApiUpdateResponse) Items() []Object {
+	return single(r.Item())
+}
+
+func (r ClientApiUpdateResponse) Count() int {
+	return len(r.Items())
+}
+
+// Items returns the updated objects.
+func (r ClientApiMultipleUpdateResponse) Items() []Object {
+	return toObjects(r.Data.Data.Objects)
+}
+
+func (r ClientApiMultipleUpdateResponse) Item() Object {
+	return first(r.Items())
+}
+
+func (r ClientApiMultipleUpdateResponse) Count() int {
+	return len(r.Data.Data.Objects)
+}
+
+func single(object Object) []Object {
+	if len(object) == 0 {
+		return nil
+	}
+
+	return []Object{object}
+}
+
+func first(objects []Object) Object {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	return objects[0]
+}
@@ -0,0 +1,109 @@
+package ucodesdk_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObject(t *testing.T) {
+	var house ucodesdk.Object
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"guid": "house-1",
+		"name": "house",
+		"price": 15000.5,
+		"room_count": "5",
+		"for_sale": true,
+		"built_at": "2024-03-01T10:30:00.000Z",
+		"owner_id": null,
+		"room_ids": ["room-1", "room-2"],
+		"room_id": "room-1",
+		"room_id_data": {"guid": "room-1", "name": "room", "size": 12.5},
+		"rooms": [{"guid": "room-1"}, {"guid": "room-2"}]
+	}`), &house))
+
+	assert.Equal(t, "house-1", house.GUID())
+	assert.Equal(t, "house", house.String("name"))
+	assert.Equal(t, 5, house.Int("room_count"))
+	assert.Equal(t, 15000.5, house.Float("price"))
+	assert.Equal(t, "30001/2", house.Decimal("price").String())
+	assert.True(t, house.Bool("for_sale"))
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), house.Time("built_at"))
+	assert.Equal(t, []string{"room-1", "room-2"}, house.Strings("room_ids"))
+	assert.Equal(t, []string{"room-1"}, house.Strings("room_id"))
+	assert.Equal(t, 12.5, house.Object("room").Float("size"))
+	assert.Equal(t, "room-2", house.Objects("rooms")[1].GUID())
+
+	// missing and invalid values
+	assert.False(t, house.Has("owner_id"))
+	assert.Equal(t, "", house.String("owner_id"))
+	assert.Equal(t, 0, house.Int("name"))
+	assert.Nil(t, house.Object("owner"))
+
+	_, err := house.StringE("owner_id")
+	assert.ErrorIs(t, err, ucodesdk.ErrMissingField)
+	_, err = house.IntE("name")
+	assert.ErrorContains(t, err, `field "name"`)
+	_, err = house.DecimalE("name")
+	assert.Error(t, err)
+	_, err = house.ObjectsE("room_ids")
+	assert.Error(t, err)
+}
+
+func TestObjectInt(t *testing.T) {
+	for value, want := range map[interface{}]int64{
+		"010":    10,
+		"08":     8,
+		"09":     9,
+		" 42 ":   42,
+		"5.00":   5,
+		"-7":     -7,
+		7.0:      7,
+		int64(3): 3,
+	} {
+		object := ucodesdk.Object{"n": value}
+
+		n, err := object.IntE("n")
+		assert.NoError(t, err, value)
+		assert.EqualValues(t, want, n, value)
+
+		n64, err := object.Int64E("n")
+		assert.NoError(t, err, value)
+		assert.Equal(t, want, n64, value)
+	}
+
+	for _, value := range []interface{}{"0x10", "1.5", "abc", ""} {
+		object := ucodesdk.Object{"n": value}
+
+		_, err := object.IntE("n")
+		assert.Error(t, err, value)
+		_, err = object.Int64E("n")
+		assert.Error(t, err, value)
+		assert.Zero(t, object.Int("n"))
+	}
+}
+
+func TestResponseItems(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	ucodeApi := server.Client("test_app_id")
+
+	created, _, err := ucodeApi.CreateObject(&ucodesdk.Argument{TableSlug: "houses", Request: ucodesdk.Request{Data: map[string]interface{}{"name": "house", "price": 15000}}})
+	assert.NoError(t, err)
+	assert.Equal(t, 15000, created.Item().Int("price"))
+
+	single, _, err := ucodeApi.GetSingleSlim(&ucodesdk.Argument{TableSlug: "houses", Request: ucodesdk.Request{Data: map[string]interface{}{"guid": created.Item().GUID()}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "house", single.Item().String("name"))
+
+	list, _, err := ucodeApi.GetListSlim(&ucodesdk.ArgumentWithPegination{TableSlug: "houses", Request: ucodesdk.Request{Data: map[string]interface{}{}}})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Items(), 1) {
+		assert.Equal(t, created.Item().GUID(), list.Items()[0].GUID())
+	}
+}
@@ -0,0 +1,250 @@
+package ucodesdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cast"
+)
+
+// ErrInvalidRelation is returned by Relations, before any request is sent, when an argument is empty.
+var ErrInvalidRelation = errors.New("invalid relation argument")
+
+type (
+	/*
+		Relations manages the many-to-many links from an object of tableFrom to objects of
+		tableTo, kept in its <tableTo>_ids field. The options are WithAppId and WithDisableFaas.
+
+		Mongo apps are sent to the many-to-many endpoint. For Postgres apps (see Capabilities)
+		the current links are read and the field is updated with UpdateObject. The read and the
+		update are not atomic, so links added or removed by another call in between are lost:
+		serialize concurrent changes of the same object on Postgres apps.
+	*/
+	Relations interface {
+		/*
+			Add is a function that links the object to the objects with the guids, linked ones are kept
+
+			Works for [Mongo, Postgres]
+		*/
+		Add(ctx context.Context, tableFrom, tableTo, idFrom string, idsTo []string, opts ...Option) error
+		/*
+			Remove is a function that unlinks the object from the objects with the guids,
+			nothing is sent when there are none
+
+			Works for [Mongo, Postgres]
+		*/
+		Remove(ctx context.Context, tableFrom, tableTo, idFrom string, idsTo []string, opts ...Option) error
+		/*
+			Set is a function that links the object to exactly the objects with the guids.
+			It reads the current links and sends one Add and one Remove at most, for the difference,
+			or one update on Postgres.
+
+			Works for [Mongo, Postgres]
+		*/
+		Set(ctx context.Context, tableFrom, tableTo, idFrom string, idsTo []string, opts ...Option) error
+		/*
+			List is a function that returns the guids of the objects linked to the object
+
+			Works for [Mongo, Postgres]
+		*/
+		List(ctx context.Context, tableFrom, tableTo, idFrom string, opts ...Option) ([]string, error)
+	}
+
+	relations struct {
+		v *v2
+	}
+)
+
+func (o *object) Relations() Relations {
+	return &relations{v: &v2{o: o}}
+}
+
+func (r *relations) Add(ctx context.Context, tableFrom, tableTo, idFrom string, idsTo []string, opts ...Option) error {
+	ids, err := validateRelation(tableFrom, tableTo, idFrom, idsTo)
+	if err != nil {
+		return err
+	}
+
+	options := newCallOptions(opts)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if r.v.o.backend(ctx, options.appId) == BackendPostgres {
+		return r.update(ctx, tableFrom, tableTo, idFrom, opts, func(current []string) []string {
+			return append(current, difference(ids, current)...)
+		})
+	}
+
+	return r.send(ctx, http.MethodPut, tableFrom, tableTo, idFrom, ids, options)
+}
+
+func (r *relations) Remove(ctx context.Context, tableFrom, tableTo, idFrom string, idsTo []string, opts ...Option) error {
+	ids, err := validateRelation(tableFrom, tableTo, idFrom, idsTo)
+	if err != nil {
+		return err
+	}
+
+	options := newCallOptions(opts)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if r.v.o.backend(ctx, options.appId) == BackendPostgres {
+		return r.update(ctx, tableFrom, tableTo, idFrom, opts, func(current []string) []string {
+			return difference(current, ids)
+		})
+	}
+
+	return r.send(ctx, http.MethodDelete, tableFrom, tableTo, idFrom, ids, options)
+}
+
+func (r *relations) Set(ctx context.Context, tableFrom, tableTo, idFrom string, idsTo []string, opts ...Option) error {
+	ids, err := validateRelation(tableFrom, tableTo, idFrom, idsTo)
+	if err != nil {
+		return err
+	}
+
+	options := newCallOptions(opts)
+	if r.v.o.backend(ctx, options.appId) == BackendPostgres {
+		return r.update(ctx, tableFrom, tableTo, idFrom, opts, func([]string) []string {
+			return ids
+		})
+	}
+
+	current, err := r.List(ctx, tableFrom, tableTo, idFrom, opts...)
+	if err != nil {
+		return err
+	}
+
+	var (
+		added   = difference(ids, current)
+		removed = difference(current, ids)
+	)
+
+	if err = r.send(ctx, http.MethodPut, tableFrom, tableTo, idFrom, added, options); err != nil {
+		return err
+	}
+
+	return r.send(ctx, http.MethodDelete, tableFrom, tableTo, idFrom, removed, options)
+}
+
+func (r *relations) List(ctx context.Context, tableFrom, tableTo, idFrom string, opts ...Option) ([]string, error) {
+	if _, err := validateRelation(tableFrom, tableTo, idFrom, nil); err != nil {
+		return nil, err
+	}
+
+	object, err := r.v.GetSingleSlim(ctx, tableFrom, idFrom, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !object.Has(tableTo + "_ids") {
+		return []string{}, nil
+	}
+
+	return object.StringsE(tableTo + "_ids")
+}
+
+// send appends (PUT) or deletes (DELETE) the links, nothing is sent without ids.
+func (r *relations) send(ctx context.Context, method, tableFrom, tableTo, idFrom string, idsTo []string, options callOptions) error {
+	if len(idsTo) == 0 {
+		return nil
+	}
+
+	var (
+		url     = fmt.Sprintf("%s/v2/items/many-to-many?from-ofs=%t", r.v.o.config.BaseURL, options.disableFaas)
+		request = map[string]interface{}{"table_from": tableFrom, "table_to": tableTo, "id_from": idFrom, "id_to": idsTo}
+	)
+
+	defer r.v.o.invalidate(options.appId, tableFrom, tableTo)
+
+	statusCode, respByte, err := r.v.o.send(ctx, url, method, request, r.v.o.headers(options.appId))
+	return decodeResponse(statusCode, respByte, err, nil)
+}
+
+// update sets the <tableTo>_ids field of the object to the links returned by change, when they differ from the current ones.
+func (r *relations) update(ctx context.Context, tableFrom, tableTo, idFrom string, opts []Option, change func(current []string) []string) error {
+	current, err := r.List(ctx, tableFrom, tableTo, idFrom, opts...)
+	if err != nil {
+		return err
+	}
+
+	links := change(current)
+	if len(difference(links, current)) == 0 && len(difference(current, links)) == 0 {
+		return nil
+	}
+
+	_, err = r.v.UpdateObject(ctx, tableFrom, map[string]interface{}{"guid": idFrom, tableTo + "_ids": links}, opts...)
+	return err
+}
+
+// manyToMany sends AppendManyToMany and DeleteManyToMany of Postgres apps with Relations.
+func (o *object) manyToMany(arg *Argument, appId string, remove bool) error {
+	var (
+		ctx       = context.Background()
+		relations = o.Relations()
+		tableFrom = cast.ToString(arg.Request.Data["table_from"])
+		tableTo   = cast.ToString(arg.Request.Data["table_to"])
+		idFrom    = cast.ToString(arg.Request.Data["id_from"])
+		idsTo     = cast.ToStringSlice(arg.Request.Data["id_to"])
+		opts      = []Option{WithAppId(appId)}
+	)
+	if arg.DisableFaas {
+		opts = append(opts, WithDisableFaas())
+	}
+
+	// an empty id_to changes nothing, Remove sends no update without guids
+	if remove {
+		return relations.Remove(ctx, tableFrom, tableTo, idFrom, idsTo, opts...)
+	}
+
+	return relations.Add(ctx, tableFrom, tableTo, idFrom, idsTo, opts...)
+}
+
+// validateRelation checks the arguments and returns the guids without duplicates.
+func validateRelation(tableFrom, tableTo, idFrom string, idsTo []string) ([]string, error) {
+	switch {
+	case tableFrom == "":
+		return nil, fmt.Errorf("table from is empty: %w", ErrInvalidRelation)
+	case tableTo == "":
+		return nil, fmt.Errorf("table to is empty: %w", ErrInvalidRelation)
+	case idFrom == "":
+		return nil, fmt.Errorf("id from is empty: %w", ErrInvalidRelation)
+	}
+
+	var (
+		ids  = make([]string, 0, len(idsTo))
+		seen = make(map[string]bool, len(idsTo))
+	)
+	for i, id := range idsTo {
+		if id == "" {
+			return nil, fmt.Errorf("id %d to %s is empty: %w", i, tableTo, ErrInvalidRelation)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// difference returns the ids of a which are not in b.
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, id := range b {
+		in[id] = true
+	}
+
+	var ids []string
+	for _, id := range a {
+		if !in[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,109 @@
+package ucodesdk_test
+
+import (
+	"context"
+	"testing"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelations(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId     = "test_app_id"
+		relations = server.Client(appId).Relations()
+		ctx       = context.Background()
+	)
+
+	houses := server.Seed(appId, "houses", map[string]interface{}{"name": "house"})
+	tags := server.Seed(appId, "tags", map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}, map[string]interface{}{"name": "c"})
+
+	ids, err := relations.List(ctx, "houses", "tags", houses[0])
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	assert.NoError(t, relations.Add(ctx, "houses", "tags", houses[0], []string{tags[0], tags[1], tags[0]}))
+	ids, err = relations.List(ctx, "houses", "tags", houses[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{tags[0], tags[1]}, ids)
+
+	sent := len(server.Requests())
+	assert.NoError(t, relations.Set(ctx, "houses", "tags", houses[0], []string{tags[1], tags[2]}))
+	// the list, one append and one delete
+	assert.Len(t, server.Requests()[sent:], 3)
+	ids, err = relations.List(ctx, "houses", "tags", houses[0])
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{tags[1], tags[2]}, ids)
+
+	sent = len(server.Requests())
+	assert.NoError(t, relations.Set(ctx, "houses", "tags", houses[0], []string{tags[2], tags[1]}))
+	assert.Len(t, server.Requests()[sent:], 1)
+
+	assert.NoError(t, relations.Remove(ctx, "houses", "tags", houses[0], []string{tags[1]}))
+	ids, err = relations.List(ctx, "houses", "tags", houses[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{tags[2]}, ids)
+
+	sent = len(server.Requests())
+	assert.ErrorIs(t, relations.Add(ctx, "houses", "", houses[0], []string{tags[0]}), ucodesdk.ErrInvalidRelation)
+	assert.ErrorIs(t, relations.Remove(ctx, "houses", "tags", houses[0], []string{""}), ucodesdk.ErrInvalidRelation)
+	assert.ErrorIs(t, relations.Set(ctx, "houses", "tags", "", nil), ucodesdk.ErrInvalidRelation)
+	assert.NoError(t, relations.Remove(ctx, "houses", "tags", houses[0], nil))
+	assert.Len(t, server.Requests()[sent:], 0)
+}
+
+func TestRelationsPostgres(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "postgres_app_id"
+		ucodeApi = ucodesdk.New(&ucodesdk.Config{AppId: "mongo_app_id", BaseURL: server.URL, Backends: map[string]ucodesdk.Backend{appId: ucodesdk.BackendPostgres}})
+		ctx      = context.Background()
+	)
+	server.UsePostgres(appId)
+
+	houses := server.Seed(appId, "houses", map[string]interface{}{"name": "house"})
+	rooms := server.Seed(appId, "room", map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}, map[string]interface{}{"name": "c"})
+
+	relations := ucodeApi.Relations()
+	assert.NoError(t, relations.Add(ctx, "houses", "room", houses[0], []string{rooms[0], rooms[1]}, ucodesdk.WithAppId(appId)))
+	assert.NoError(t, relations.Set(ctx, "houses", "room", houses[0], []string{rooms[1], rooms[2]}, ucodesdk.WithAppId(appId)))
+	assert.NoError(t, relations.Remove(ctx, "houses", "room", houses[0], []string{rooms[2]}, ucodesdk.WithAppId(appId)))
+
+	ids, err := relations.List(ctx, "houses", "room", houses[0], ucodesdk.WithAppId(appId))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{rooms[1]}, ids)
+
+	_, err = ucodeApi.AppendManyToMany(&ucodesdk.Argument{
+		AppId:     appId,
+		TableSlug: "houses",
+		Request:   ucodesdk.Request{Data: map[string]interface{}{"table_from": "houses", "table_to": "room", "id_from": houses[0], "id_to": []string{rooms[0]}}},
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{rooms[0], rooms[1]}, server.Objects(appId, "houses")[0]["room_ids"])
+
+	_, err = ucodeApi.DeleteManyToMany(&ucodesdk.Argument{
+		AppId:     appId,
+		TableSlug: "houses",
+		Request:   ucodesdk.Request{Data: map[string]interface{}{"table_from": "houses", "table_to": "room", "id_from": houses[0], "id_to": []string{}}},
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{rooms[0], rooms[1]}, server.Objects(appId, "houses")[0]["room_ids"])
+
+	_, err = ucodeApi.DeleteManyToMany(&ucodesdk.Argument{
+		AppId:     appId,
+		TableSlug: "houses",
+		Request:   ucodesdk.Request{Data: map[string]interface{}{"table_from": "houses", "table_to": "room", "id_from": houses[0], "id_to": []string{rooms[0]}}},
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{rooms[1]}, server.Objects(appId, "houses")[0]["room_ids"])
+
+	for _, request := range server.Requests() {
+		assert.NotContains(t, request, "many-to-many")
+	}
+}
@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const goldenSuffix = ".golden.json"
+
+type (
+	// Fixture is one invocation of the handler.
+	//
+	// The on-disk shape is the same as template/request.json, i.e. the body uCode
+	// posts to a function. In the requests.jsonl format every line is a Fixture
+	// and may carry its expected response inline.
+	Fixture struct {
+		Name     string          `json:"name,omitempty"`
+		Data     json.RawMessage `json:"data"`
+		Expected *Golden         `json:"expected,omitempty"`
+
+		// goldenPath is where the expected response of a file fixture lives.
+		// It is empty for jsonl fixtures.
+		goldenPath string
+	}
+
+	// Golden is the expected response of a fixture.
+	Golden struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	}
+)
+
+/*
+LoadFixtures reads fixtures from the given paths.
+
+A path may be a single .json fixture, a .jsonl file with one fixture per line
+or a directory whose .json and .jsonl files are loaded in name order.
+Golden files (*.golden.json) are never treated as fixtures.
+*/
+func LoadFixtures(paths ...string) ([]*Fixture, error) {
+	var fixtures []*Fixture
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			loaded, err := loadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			fixtures = append(fixtures, loaded...)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var names []string
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || strings.HasSuffix(name, goldenSuffix) {
+				continue
+			}
+			if ext := filepath.Ext(name); ext == ".json" || ext == ".jsonl" {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			loaded, err := loadFile(filepath.Join(path, name))
+			if err != nil {
+				return nil, err
+			}
+			fixtures = append(fixtures, loaded...)
+		}
+	}
+
+	return fixtures, nil
+}
+
+func loadFile(path string) ([]*Fixture, error) {
+	if filepath.Ext(path) == ".jsonl" {
+		return loadJSONLines(path)
+	}
+
+	fileByte, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err = json.Unmarshal(fileByte, &fixture); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if fixture.Name == "" {
+		fixture.Name = path
+	}
+	fixture.goldenPath = strings.TrimSuffix(path, filepath.Ext(path)) + goldenSuffix
+
+	if fixture.Expected == nil {
+		goldenByte, err := os.ReadFile(fixture.goldenPath)
+		switch {
+		case err == nil:
+			fixture.Expected = &Golden{}
+			if err = json.Unmarshal(goldenByte, fixture.Expected); err != nil {
+				return nil, fmt.Errorf("%s: %w", fixture.goldenPath, err)
+			}
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+
+	return []*Fixture{&fixture}, nil
+}
+
+func loadJSONLines(path string) ([]*Fixture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		fixtures []*Fixture
+		scanner  = bufio.NewScanner(file)
+		line     int
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line++
+
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var fixture Fixture
+		if err = json.Unmarshal(text, &fixture); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+
+		if fixture.Name == "" {
+			fixture.Name = fmt.Sprintf("%s:%d", path, line)
+		}
+
+		fixtures = append(fixtures, &fixture)
+	}
+
+	return fixtures, scanner.Err()
+}
+
+// applyOverrides sets app_id, table_slug and method of the fixture data when they are given.
+func (f *Fixture) applyOverrides(opts Options) (json.RawMessage, error) {
+	if opts.AppId == "" && opts.TableSlug == "" && opts.Method == "" {
+		return f.Data, nil
+	}
+
+	data := map[string]interface{}{}
+	if len(f.Data) > 0 && string(f.Data) != "null" {
+		if err := json.Unmarshal(f.Data, &data); err != nil {
+			return nil, fmt.Errorf("%s: data must be an object to apply overrides: %w", f.Name, err)
+		}
+	}
+
+	if opts.AppId != "" {
+		data["app_id"] = opts.AppId
+	}
+	if opts.TableSlug != "" {
+		data["table_slug"] = opts.TableSlug
+	}
+	if opts.Method != "" {
+		data["method"] = opts.Method
+	}
+
+	return json.Marshal(data)
+}
@@ -0,0 +1,247 @@
+/*
+Package runner runs a function handler locally against request fixtures.
+
+It is the engine behind the ucode-run command of a function project
+(cmd/main.go), which is compiled together with the handler:
+
+	func main() {
+		runner.Main(function.Handle(), "template/request.json")
+	}
+
+and used as
+
+	go run ./template/cmd -app-id P-xxx -method CREATE template/testdata/
+	go run ./template/cmd serve -addr :8080 -fake-backend
+*/
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+)
+
+// ErrMismatch is returned by Run when at least one fixture did not match its golden response.
+var ErrMismatch = errors.New("response does not match expected")
+
+type (
+	// Options changes the way fixtures are sent to the handler.
+	Options struct {
+		// AppId, TableSlug and Method override data.app_id, data.table_slug
+		// and data.method of every fixture when they are not empty.
+		AppId     string
+		TableSlug string
+		Method    string
+
+		// Update writes the actual responses to the golden files instead of comparing them.
+		Update bool
+		// Verbose prints response bodies of passing fixtures too.
+		Verbose bool
+	}
+
+	// Result is the outcome of one fixture.
+	Result struct {
+		Fixture    *Fixture
+		StatusCode int
+		Body       []byte
+		// Compared is false when the fixture has no expected response.
+		Compared bool
+		Passed   bool
+		Err      error
+	}
+)
+
+/*
+Main parses the command line, runs the handler against the fixtures and exits.
+
+The exit code is 0 when every fixture passed, 1 when a response did not match
+its golden file and 2 on usage or fixture errors.
+"serve" as the first argument hosts the handler on a local port instead, see Serve.
+defaultPaths are used when no fixture path is given on the command line.
+*/
+func Main(handler http.Handler, defaultPaths ...string) {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		serveMain(handler, os.Args[2:])
+		return
+	}
+
+	var (
+		opts  Options
+		flags = flag.NewFlagSet("ucode-run", flag.ExitOnError)
+	)
+
+	flags.StringVar(&opts.AppId, "app-id", "", "override data.app_id of every fixture")
+	flags.StringVar(&opts.TableSlug, "table-slug", "", "override data.table_slug of every fixture")
+	flags.StringVar(&opts.Method, "method", "", "override data.method of every fixture (CREATE, UPDATE, ...)")
+	flags.BoolVar(&opts.Update, "update", false, "write actual responses to golden files")
+	flags.BoolVar(&opts.Verbose, "v", false, "print response bodies of passing fixtures")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ucode-run [flags] [fixture.json | fixtures.jsonl | dir]...\n\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = defaultPaths
+	}
+	if len(paths) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	fixtures, err := LoadFixtures(paths...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error loading fixtures:", err)
+		os.Exit(2)
+	}
+
+	_, err = Run(handler, fixtures, opts, os.Stdout)
+	switch {
+	case errors.Is(err, ErrMismatch):
+		os.Exit(1)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
+
+/*
+Run invokes the handler once per fixture and reports every result to out.
+
+It returns ErrMismatch when a response differs from its expected one,
+or the first fixture error when a fixture could not be run at all.
+*/
+func Run(handler http.Handler, fixtures []*Fixture, opts Options, out io.Writer) ([]Result, error) {
+	var (
+		results  = make([]Result, 0, len(fixtures))
+		failed   int
+		firstErr error
+	)
+
+	for _, fixture := range fixtures {
+		result := runOne(handler, fixture, opts)
+		results = append(results, result)
+
+		switch {
+		case result.Err != nil:
+			failed++
+			if firstErr == nil {
+				firstErr = result.Err
+			}
+			fmt.Fprintf(out, "ERROR %s: %v\n", fixture.Name, result.Err)
+		case !result.Compared:
+			fmt.Fprintf(out, "RUN   %s\nStatus: %d\nBody: %s\n", fixture.Name, result.StatusCode, result.Body)
+		case result.Passed:
+			fmt.Fprintf(out, "PASS  %s\n", fixture.Name)
+			if opts.Verbose {
+				fmt.Fprintf(out, "Status: %d\nBody: %s\n", result.StatusCode, result.Body)
+			}
+		default:
+			failed++
+			fmt.Fprintf(out, "FAIL  %s\n", fixture.Name)
+			fmt.Fprintf(out, "  expected status: %d\n  actual status:   %d\n", fixture.Expected.StatusCode, result.StatusCode)
+			fmt.Fprintf(out, "  expected body: %s\n  actual body:   %s\n", fixture.Expected.Body, result.Body)
+		}
+	}
+
+	fmt.Fprintf(out, "\n%d fixtures, %d failed\n", len(fixtures), failed)
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	if failed > 0 {
+		return results, ErrMismatch
+	}
+
+	return results, nil
+}
+
+func runOne(handler http.Handler, fixture *Fixture, opts Options) Result {
+	result := Result{Fixture: fixture}
+
+	data, err := fixture.applyOverrides(opts)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	body, err := json.Marshal(map[string]json.RawMessage{"data": data})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	request, err := http.NewRequest(http.MethodPost, "/", bytes.NewBuffer(body))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	result.StatusCode = recorder.Code
+	result.Body = recorder.Body.Bytes()
+
+	if opts.Update {
+		result.Err = writeGolden(fixture, result)
+		return result
+	}
+
+	if fixture.Expected == nil {
+		return result
+	}
+
+	result.Compared = true
+	result.Passed = fixture.Expected.StatusCode == result.StatusCode && equalJSON(fixture.Expected.Body, result.Body)
+
+	return result
+}
+
+func writeGolden(fixture *Fixture, result Result) error {
+	if fixture.goldenPath == "" {
+		return fmt.Errorf("%s: golden responses of jsonl fixtures must be updated by hand", fixture.Name)
+	}
+
+	body := json.RawMessage(result.Body)
+	if !json.Valid(body) {
+		encoded, err := json.Marshal(string(result.Body))
+		if err != nil {
+			return err
+		}
+		body = encoded
+	}
+
+	goldenByte, err := json.MarshalIndent(Golden{StatusCode: result.StatusCode, Body: body}, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(fixture.goldenPath, append(goldenByte, '\n'), 0o644)
+}
+
+// equalJSON compares two bodies semantically when both are JSON and byte by byte otherwise.
+func equalJSON(expected, actual []byte) bool {
+	var expectedValue, actualValue interface{}
+
+	if err := json.Unmarshal(expected, &expectedValue); err != nil {
+		return bytes.Equal(expected, actual)
+	}
+
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		// a non JSON body is stored in the golden file as a JSON string
+		text, ok := expectedValue.(string)
+		return ok && text == string(actual)
+	}
+
+	return reflect.DeepEqual(expectedValue, actualValue)
+}
@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// echoHandler answers with the data it received.
+var echoHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	body, _ := io.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": "done", "data": request.Data})
+})
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("create.json", `{"data": {"object_data": {"name": "house"}}}`)
+	writeFile("create.golden.json", `{"status_code": 200, "body": {"status": "done", "data": {"object_data": {"name": "house"}}}}`)
+	writeFile("update.json", `{"data": {"object_data": {"name": "room"}}}`)
+	writeFile("update.golden.json", `{"status_code": 200, "body": {"status": "done", "data": {}}}`)
+	writeFile("many.jsonl", `{"name": "first", "data": {"table_slug": "houses"}, "expected": {"status_code": 200, "body": {"status": "done", "data": {"table_slug": "rooms", "method": "CREATE"}}}}
+{"data": {}}
+`)
+
+	t.Run("loadFixtures", func(t *testing.T) {
+		fixtures, err := LoadFixtures(dir)
+		assert.NoError(t, err)
+		if !assert.Len(t, fixtures, 4) {
+			return
+		}
+
+		assert.Equal(t, filepath.Join(dir, "create.json"), fixtures[0].Name)
+		assert.Equal(t, "first", fixtures[1].Name)
+		assert.Equal(t, filepath.Join(dir, "many.jsonl")+":2", fixtures[2].Name)
+		assert.Nil(t, fixtures[2].Expected)
+		assert.NotNil(t, fixtures[3].Expected)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		fixtures, err := LoadFixtures(dir)
+		assert.NoError(t, err)
+
+		results, err := Run(echoHandler, fixtures, Options{TableSlug: "rooms", Method: "CREATE"}, io.Discard)
+		assert.ErrorIs(t, err, ErrMismatch)
+
+		// create.json does not expect the overridden fields, many.jsonl does
+		assert.False(t, results[0].Passed)
+		assert.True(t, results[1].Passed)
+		assert.False(t, results[2].Compared)
+		assert.False(t, results[3].Passed)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		fixtures, err := LoadFixtures(filepath.Join(dir, "update.json"))
+		assert.NoError(t, err)
+
+		_, err = Run(echoHandler, fixtures, Options{Update: true}, io.Discard)
+		assert.NoError(t, err)
+
+		fixtures, err = LoadFixtures(filepath.Join(dir, "update.json"), filepath.Join(dir, "create.json"))
+		assert.NoError(t, err)
+
+		_, err = Run(echoHandler, fixtures, Options{}, io.Discard)
+		assert.NoError(t, err)
+	})
+}
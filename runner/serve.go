@@ -0,0 +1,169 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+)
+
+// BaseURLEnv overrides the uCode base url of the handler. The template handler reads it on every invocation.
+const BaseURLEnv = "UCODE_BASE_URL"
+
+// ServeOptions configures Serve.
+type ServeOptions struct {
+	// Addr is the address the handler is served on, ":8080" by default.
+	Addr string
+	// BaseURL is exported as UCODE_BASE_URL for the handler when it is not empty, see Serve.
+	BaseURL string
+	// FakeBackend starts an in-memory ucodetest backend and points the handler at it.
+	FakeBackend bool
+	// Seed is a JSON file with objects to load into the fake backend under SeedAppId.
+	Seed      string
+	SeedAppId string
+}
+
+/*
+Serve hosts the handler on a local port until ctx is cancelled.
+
+Invocations are accepted on "/" and on "/function/<name>" like the
+OpenFaaS gateway, so the same url shape works from Postman or the frontend.
+
+The handler is pointed at BaseURL, or the fake backend, through the
+UCODE_BASE_URL environment variable, which the template handler reads on
+every invocation. It is set for the whole process while Serve runs and
+restored when it returns.
+*/
+func Serve(ctx context.Context, handler http.Handler, opts ServeOptions, logger *log.Logger) error {
+	if opts.Addr == "" {
+		opts.Addr = ":8080"
+	}
+
+	if opts.FakeBackend {
+		backend := ucodetest.NewServer()
+		defer backend.Close()
+
+		if opts.Seed != "" {
+			seed, err := os.Open(opts.Seed)
+			if err != nil {
+				return err
+			}
+			err = backend.LoadSeed(opts.SeedAppId, seed)
+			seed.Close()
+			if err != nil {
+				return fmt.Errorf("seed %s: %w", opts.Seed, err)
+			}
+		}
+
+		opts.BaseURL = backend.URL
+		logger.Printf("fake uCode backend listening on %s", backend.URL)
+	}
+
+	if opts.BaseURL != "" {
+		previous, set := os.LookupEnv(BaseURLEnv)
+		if err := os.Setenv(BaseURLEnv, opts.BaseURL); err != nil {
+			return err
+		}
+		defer func() {
+			if set {
+				os.Setenv(BaseURLEnv, previous)
+			} else {
+				os.Unsetenv(BaseURLEnv)
+			}
+		}()
+	}
+
+	server := &http.Server{
+		Addr:    opts.Addr,
+		Handler: logRequests(handler, logger),
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	logger.Printf("serving function on %s", opts.Addr)
+
+	select {
+	case err := <-errCh:
+		// e.g. the address is in use
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	// ListenAndServe returns ErrServerClosed once Shutdown is called
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func serveMain(handler http.Handler, args []string) {
+	var (
+		opts  ServeOptions
+		flags = flag.NewFlagSet("ucode-run serve", flag.ExitOnError)
+	)
+
+	flags.StringVar(&opts.Addr, "addr", ":8080", "address to serve the function on")
+	flags.StringVar(&opts.BaseURL, "base-url", "", "uCode base url used by the handler")
+	flags.BoolVar(&opts.FakeBackend, "fake-backend", false, "point the handler at an in-memory uCode backend")
+	flags.StringVar(&opts.Seed, "seed", "", "JSON file with objects for the fake backend, keyed by table slug")
+	flags.StringVar(&opts.SeedAppId, "seed-app-id", "", "app id the seed objects belong to")
+	_ = flags.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := Serve(ctx, handler, opts, log.New(os.Stderr, "", log.LstdFlags)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func logRequests(handler http.Handler, logger *log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			start    = time.Now()
+			recorder = &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		)
+
+		if r.Method == http.MethodOptions {
+			// let the frontend call the local function
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.Header().Set("Access-Control-Allow-Headers", "*")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		handler.ServeHTTP(recorder, r)
+
+		logger.Printf("%s %s %d %s", r.Method, r.URL.Path, recorder.statusCode, time.Since(start).Round(time.Millisecond))
+	})
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	t.Setenv(BaseURLEnv, "https://api.example.com")
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		served      = make(chan error, 1)
+		handler     = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, os.Getenv(BaseURLEnv))
+		})
+	)
+	defer cancel()
+
+	go func() {
+		served <- Serve(ctx, handler, ServeOptions{Addr: addr, FakeBackend: true}, log.New(io.Discard, "", 0))
+	}()
+
+	var resp *http.Response
+	for i := 0; i < 100; i++ {
+		if resp, err = http.Post("http://"+addr+"/function/test", "application/json", strings.NewReader(`{}`)); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		// the handler is pointed at the fake backend
+		assert.True(t, strings.HasPrefix(string(body), "http://127.0.0.1:"), string(body))
+	}
+
+	// the address is in use
+	assert.Error(t, Serve(context.Background(), handler, ServeOptions{Addr: addr}, log.New(io.Discard, "", 0)))
+
+	cancel()
+	assert.NoError(t, <-served)
+	assert.Equal(t, "https://api.example.com", os.Getenv(BaseURLEnv))
+}
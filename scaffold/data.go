@@ -0,0 +1,87 @@
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	templateData struct {
+		Name     string
+		Module   string
+		Triggers []Trigger
+		Handlers []handlerData
+		HTTP     bool
+	}
+
+	// handlerData is one typed stub of the handler and its fixture.
+	handlerData struct {
+		FuncName    string
+		FixtureName string
+		TableSlug   string
+		When        string
+		Method      string
+	}
+)
+
+func newTemplateData(opts Options) (templateData, error) {
+	data := templateData{
+		Name:     opts.Name,
+		Module:   opts.Module,
+		Triggers: opts.Triggers,
+	}
+
+	// uCode does not tell BEFORE from AFTER in the request, so a table and method pair can only be handled once
+	seen := map[string]string{}
+
+	for _, trigger := range opts.Triggers {
+		if err := trigger.validate(); err != nil {
+			return templateData{}, err
+		}
+
+		if trigger.When == HTTP {
+			if !data.HTTP {
+				data.HTTP = true
+				data.Handlers = append(data.Handlers, handlerData{FuncName: "handleHTTP", FixtureName: "http", When: HTTP})
+			}
+			continue
+		}
+
+		for _, method := range trigger.Methods {
+			key := trigger.TableSlug + ":" + method
+			if when, ok := seen[key]; ok {
+				return templateData{}, fmt.Errorf("%s %s is triggered both %s and %s", trigger.TableSlug, method, when, trigger.When)
+			}
+			seen[key] = trigger.When
+
+			data.Handlers = append(data.Handlers, handlerData{
+				FuncName:    lowerFirst(camelCase(trigger.TableSlug) + camelCase(trigger.When) + camelCase(method)),
+				FixtureName: strings.ToLower(trigger.TableSlug + "_" + trigger.When + "_" + method),
+				TableSlug:   trigger.TableSlug,
+				When:        trigger.When,
+				Method:      method,
+			})
+		}
+	}
+
+	return data, nil
+}
+
+// camelCase turns "multiple_update" and "room-types" into "MultipleUpdate" and "RoomTypes".
+func camelCase(s string) string {
+	var b strings.Builder
+
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' }) {
+		b.WriteString(strings.ToUpper(word[:1]) + strings.ToLower(word[1:]))
+	}
+
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,265 @@
+/*
+Package scaffold generates new uCode function projects from versioned templates.
+
+The templates are embedded into the binary, every generated project records
+the template version it was created from in .ucode.json.
+*/
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Version is the version of the templates used by Generate.
+const Version = "v1"
+
+// fixtureTemplate is rendered once per handler instead of once per project.
+const fixtureTemplate = "fixture.json.tmpl"
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	// ErrExists is returned when a generated file already exists and Options.Force is false.
+	ErrExists = errors.New("file already exists")
+
+	nameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+)
+
+// Moments a function can be invoked at.
+const (
+	Before = "BEFORE"
+	After  = "AFTER"
+	HTTP   = "HTTP"
+)
+
+// Methods lists the actions a BEFORE or AFTER function can be attached to.
+var Methods = []string{"CREATE", "UPDATE", "MULTIPLE_UPDATE", "DELETE", "APPEND_MANY2MANY", "DELETE_MANY2MANY"}
+
+type (
+	// Trigger describes when the function is invoked.
+	Trigger struct {
+		TableSlug string   `json:"table_slug,omitempty"`
+		When      string   `json:"when"`
+		Methods   []string `json:"methods,omitempty"`
+	}
+
+	Options struct {
+		// Name of the function, it is also used as FunctionName of the SDK config.
+		Name string
+		// Module is the go module path of the project, Name by default.
+		Module string
+		// Dir the project is written to, Name by default.
+		Dir      string
+		Triggers []Trigger
+		// Force overwrites existing files.
+		Force bool
+	}
+
+	// manifest is written to .ucode.json of the generated project.
+	manifest struct {
+		Name            string    `json:"name"`
+		TemplateVersion string    `json:"template_version"`
+		Triggers        []Trigger `json:"triggers"`
+	}
+)
+
+/*
+ParseTrigger parses a trigger given as
+
+	table_slug:WHEN:METHOD[,METHOD...]	e.g. houses:AFTER:CREATE,UPDATE
+	HTTP
+*/
+func ParseTrigger(s string) (Trigger, error) {
+	if strings.EqualFold(s, HTTP) {
+		return Trigger{When: HTTP}, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return Trigger{}, fmt.Errorf("invalid trigger %q, expected table_slug:BEFORE|AFTER:METHOD[,METHOD] or HTTP", s)
+	}
+
+	trigger := Trigger{
+		TableSlug: parts[0],
+		When:      strings.ToUpper(parts[1]),
+	}
+	for _, method := range strings.Split(parts[2], ",") {
+		trigger.Methods = append(trigger.Methods, strings.ToUpper(strings.TrimSpace(method)))
+	}
+
+	return trigger, trigger.validate()
+}
+
+func (t Trigger) validate() error {
+	if t.When == HTTP {
+		return nil
+	}
+
+	if !nameRegexp.MatchString(t.TableSlug) {
+		return fmt.Errorf("invalid table slug %q", t.TableSlug)
+	}
+
+	if t.When != Before && t.When != After {
+		return fmt.Errorf("invalid trigger moment %q, expected %s, %s or %s", t.When, Before, After, HTTP)
+	}
+
+	if len(t.Methods) == 0 {
+		return fmt.Errorf("trigger on %s has no methods", t.TableSlug)
+	}
+
+	for _, method := range t.Methods {
+		if !contains(Methods, method) {
+			return fmt.Errorf("invalid method %q, expected one of %s", method, strings.Join(Methods, ", "))
+		}
+	}
+
+	return nil
+}
+
+func (t Trigger) String() string {
+	if t.When == HTTP {
+		return HTTP
+	}
+
+	return fmt.Sprintf("%s -> %s -> %s", t.TableSlug, t.When, strings.Join(t.Methods, " | "))
+}
+
+/*
+Generate writes a new function project and returns the paths of the written files.
+
+The project contains a handler with a typed stub per trigger, fixtures for
+every trigger in testdata/, a test running them against the in-memory
+ucodetest backend and the cmd/main.go runner.
+*/
+func Generate(opts Options) ([]string, error) {
+	if !nameRegexp.MatchString(opts.Name) {
+		return nil, fmt.Errorf("invalid function name %q", opts.Name)
+	}
+	if opts.Module == "" {
+		opts.Module = opts.Name
+	}
+	if opts.Dir == "" {
+		opts.Dir = opts.Name
+	}
+	if len(opts.Triggers) == 0 {
+		opts.Triggers = []Trigger{{When: HTTP}}
+	}
+
+	data, err := newTemplateData(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+
+	root := path.Join("templates", Version)
+	err = fs.WalkDir(templates, root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || name == path.Join(root, fixtureTemplate) {
+			return err
+		}
+
+		content, err := render(name, data)
+		if err != nil {
+			return err
+		}
+
+		files[strings.TrimSuffix(strings.TrimPrefix(name, root+"/"), ".tmpl")] = content
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fixture := range data.Handlers {
+		content, err := render(path.Join(root, fixtureTemplate), fixture)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Join("testdata", fixture.FixtureName+".json")] = content
+	}
+
+	manifestByte, err := json.MarshalIndent(manifest{Name: opts.Name, TemplateVersion: Version, Triggers: opts.Triggers}, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	files[".ucode.json"] = append(manifestByte, '\n')
+
+	if !opts.Force {
+		for name := range files {
+			if _, err := os.Stat(filepath.Join(opts.Dir, name)); err == nil {
+				return nil, fmt.Errorf("%s: %w", filepath.Join(opts.Dir, name), ErrExists)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	written := make([]string, 0, len(files))
+	for _, name := range names {
+		var (
+			content = files[name]
+			target  = filepath.Join(opts.Dir, filepath.FromSlash(name))
+		)
+
+		if err = os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return written, err
+		}
+		if err = os.WriteFile(target, content, 0o644); err != nil {
+			return written, err
+		}
+
+		written = append(written, target)
+	}
+
+	return written, nil
+}
+
+func render(name string, data interface{}) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(name, ".go.tmpl") {
+		formatted, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return formatted, nil
+	}
+
+	return buf.Bytes(), nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
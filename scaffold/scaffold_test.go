@@ -0,0 +1,39 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+
+	trigger, err := ParseTrigger("room_types:before:create,multiple_update")
+	assert.NoError(t, err)
+	assert.Equal(t, Trigger{TableSlug: "room_types", When: Before, Methods: []string{"CREATE", "MULTIPLE_UPDATE"}}, trigger)
+
+	_, err = ParseTrigger("houses:AFTER:PATCH")
+	assert.Error(t, err)
+
+	written, err := Generate(Options{Name: "notify", Dir: dir, Module: "example.com/notify", Triggers: []Trigger{trigger}})
+	assert.NoError(t, err)
+	assert.Contains(t, written, filepath.Join(dir, "testdata", "room_types_before_multiple_update.json"))
+
+	handler, err := os.ReadFile(filepath.Join(dir, "handler.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(handler), "func roomTypesBeforeMultipleUpdate(ucodeApi sdk.UcodeApis, data sdk.Data) (sdk.Response, error)")
+
+	// existing projects are not overwritten by accident
+	_, err = Generate(Options{Name: "notify", Dir: dir})
+	assert.ErrorIs(t, err, ErrExists)
+
+	// BEFORE and AFTER of the same method can not be told apart
+	_, err = Generate(Options{Name: "notify", Dir: t.TempDir(), Triggers: []Trigger{
+		{TableSlug: "houses", When: Before, Methods: []string{"CREATE"}},
+		{TableSlug: "houses", When: After, Methods: []string{"CREATE"}},
+	}})
+	assert.Error(t, err)
+}
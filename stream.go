@@ -0,0 +1,164 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds Config.MaxResponseSize.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// listPath is where the objects are in GET_LIST and GET_LIST_SLIM responses, see GetListClientApiResponse.
+var listPath = []string{"data", "data", "response"}
+
+func (o *object) GetListStream(ctx context.Context, arg *ArgumentWithPegination, fn func(object map[string]interface{}) error) (int, error) {
+	var (
+		url           = fmt.Sprintf("%s/v2/object/get-list/%s?from-ofs=%t", o.config.BaseURL, arg.TableSlug, arg.DisableFaas)
+		offset, limit = arg.offsetLimit()
+		data          = make(map[string]interface{}, len(arg.Request.Data)+2)
+	)
+
+	// unlike GetList, the argument is not modified
+	for key, value := range arg.Request.Data {
+		data[key] = value
+	}
+	data["offset"] = offset
+	data["limit"] = limit
+
+	return o.streamList(ctx, url, http.MethodPost, Request{Data: data, IsCached: arg.Request.IsCached}, arg.AppId, fn)
+}
+
+func (o *object) GetListSlimStream(ctx context.Context, arg *ArgumentWithPegination, fn func(object map[string]interface{}) error) (int, error) {
+	reqObject, err := json.Marshal(arg.Request.Data)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		offset, limit = arg.offsetLimit()
+		url           = fmt.Sprintf("%s/v2/object-slim/get-list/%s?from-ofs=%t&data=%s&offset=%d&limit=%d",
+			o.config.BaseURL, arg.TableSlug, arg.DisableFaas, escapeQuery(reqObject), offset, limit)
+	)
+
+	return o.streamList(ctx, url, http.MethodGet, nil, arg.AppId, fn)
+}
+
+// streamList sends a list request and calls fn with every object of the response as it is decoded.
+func (o *object) streamList(ctx context.Context, url, method string, body interface{}, appId string, fn func(object map[string]interface{}) error) (int, error) {
+	resp, err := o.do(ctx, url, method, body, o.headers(appId))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		respByte, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return 0, err
+		}
+		return 0, &HTTPError{StatusCode: resp.StatusCode, Body: respByte}
+	}
+
+	return decodeList(json.NewDecoder(resp.Body), fn)
+}
+
+// decodeList finds the array at listPath and decodes its objects one by one.
+func decodeList(decoder *json.Decoder, fn func(object map[string]interface{}) error) (int, error) {
+	found, err := findArray(decoder, listPath)
+	if err != nil || !found {
+		return 0, err
+	}
+
+	var count int
+	for decoder.More() {
+		var object map[string]interface{}
+		if err = decoder.Decode(&object); err != nil {
+			return count, err
+		}
+
+		if err = fn(object); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// findArray moves the decoder into the array at path. It returns false when a value on the path is missing or null.
+func findArray(decoder *json.Decoder, path []string) (bool, error) {
+	token, err := decoder.Token()
+	if err != nil || token == nil {
+		return false, err
+	}
+
+	if len(path) == 0 {
+		if token != json.Delim('[') {
+			return false, fmt.Errorf("expected an array of objects, got %v", token)
+		}
+		return true, nil
+	}
+
+	if token != json.Delim('{') {
+		return false, fmt.Errorf("expected an object at %q, got %v", path[0], token)
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return false, err
+		}
+
+		if key == path[0] {
+			return findArray(decoder, path[1:])
+		}
+
+		var skip json.RawMessage
+		if err = decoder.Decode(&skip); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+func (arg *ArgumentWithPegination) offsetLimit() (int, int) {
+	page, limit := arg.Page, arg.Limit
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	return (page - 1) * limit, limit
+}
+
+func tooLarge(max int64) error {
+	return fmt.Errorf("%w, the limit is %d bytes", ErrResponseTooLarge, max)
+}
+
+// limitedBody fails with ErrResponseTooLarge once more than remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	limit     int64
+	remaining int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.ReadCloser.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, tooLarge(l.limit)
+	}
+
+	return n, err
+}
@@ -0,0 +1,93 @@
+package ucodesdk_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetListStream(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId)
+		ctx      = context.Background()
+	)
+	for i := 0; i < 25; i++ {
+		server.Seed(appId, "houses", map[string]interface{}{"name": fmt.Sprintf("house_%d", i), "price": 15000 + i%2})
+	}
+
+	t.Run("slim", func(t *testing.T) {
+		var names []string
+		count, err := ucodeApi.Bulk().GetListSlimStream(ctx, &ucodesdk.ArgumentWithPegination{
+			TableSlug: "houses",
+			Request:   ucodesdk.Request{Data: map[string]interface{}{"price": 15001}},
+			Limit:     5,
+			Page:      2,
+		}, func(object map[string]interface{}) error {
+			names = append(names, object["name"].(string))
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, count)
+		assert.Equal(t, []string{"house_11", "house_13", "house_15", "house_17", "house_19"}, names)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		stop := errors.New("stop")
+		count, err := ucodeApi.Bulk().GetListStream(ctx, &ucodesdk.ArgumentWithPegination{TableSlug: "houses", Limit: 20}, func(object map[string]interface{}) error {
+			if object["name"] == "house_3" {
+				return stop
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		server.FailWhen(func(method, path string, body []byte) bool { return true })
+		defer server.FailWhen(nil)
+
+		_, err := ucodeApi.Bulk().GetListSlimStream(ctx, &ucodesdk.ArgumentWithPegination{TableSlug: "houses"}, func(map[string]interface{}) error { return nil })
+		var httpErr *ucodesdk.HTTPError
+		assert.ErrorAs(t, err, &httpErr)
+	})
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	body := `{"status": "OK", "data": {"data": {"count": 3, "response": [{"name": "a"}, {"name": "b"}, {"name": "` + strings.Repeat("c", 1000) + `"}]}}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// flushing first sends the body chunked, without a Content-Length
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	ucodeApi := ucodesdk.New(&ucodesdk.Config{BaseURL: server.URL, AppId: "test_app_id", MaxResponseSize: 500})
+
+	var names []string
+	count, err := ucodeApi.Bulk().GetListSlimStream(context.Background(), &ucodesdk.ArgumentWithPegination{TableSlug: "houses"}, func(object map[string]interface{}) error {
+		names = append(names, object["name"].(string))
+		return nil
+	})
+	assert.ErrorIs(t, err, ucodesdk.ErrResponseTooLarge)
+	assert.Equal(t, 2, count)
+
+	_, _, err = ucodeApi.GetListSlim(&ucodesdk.ArgumentWithPegination{TableSlug: "houses"})
+	assert.ErrorIs(t, err, ucodesdk.ErrResponseTooLarge)
+
+	ucodeApi.Config().MaxResponseSize = 0
+	_, err = ucodeApi.Bulk().GetListSlimStream(context.Background(), &ucodesdk.ArgumentWithPegination{TableSlug: "houses"}, func(map[string]interface{}) error { return nil })
+	assert.NoError(t, err)
+}
@@ -1,58 +1,21 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
-	"net/http/httptest"
-	"os"
-
+	"github.com/golanguzb70/ucode-sdk/runner"
 	function "github.com/golanguzb70/ucode-sdk/template"
 )
 
-func main() {
-	// Open and read the JSON file
-	jsonFile, err := os.Open("template/request.json")
-	if err != nil {
-		fmt.Println("Error opening JSON file:", err)
-		return
-	}
-	defer jsonFile.Close()
-
-	byteValue, err := ioutil.ReadAll(jsonFile)
-	if err != nil {
-		fmt.Println("Error reading JSON file:", err)
-		return
-	}
-
-	// Parse the JSON into a struct
-	var requestData struct {
-		Data json.RawMessage `json:"data"`
-	}
-	err = json.Unmarshal(byteValue, &requestData)
-	if err != nil {
-		fmt.Println("Error parsing JSON:", err)
-		return
-	}
+/*
+ucode-run invokes the handler locally against request fixtures.
 
-	// Create a new request
-	req, err := http.NewRequest("", "", bytes.NewBuffer(requestData.Data))
-	if err != nil {
-		fmt.Println("Error creating request:", err)
-		return
-	}
-	// Create a response recorder
-	rr := httptest.NewRecorder()
+	go run ./template/cmd                                 # runs template/request.json
+	go run ./template/cmd -method UPDATE fixtures/        # every fixture of a directory
+	go run ./template/cmd requests.jsonl                  # one fixture per line
+	go run ./template/cmd -update fixtures/               # (re)write golden files
 
-	// Call the handler
-	handler := function.Handle()
-
-	// Invoke the handler
-	handler.ServeHTTP(rr, req)
-
-	// Print the response
-	fmt.Printf("Status: %d\n", rr.Code)
-	fmt.Printf("Body: %s\n", rr.Body.String())
+Responses are compared with <fixture>.golden.json when it exists
+and the command exits non-zero on mismatch.
+*/
+func main() {
+	runner.Main(function.Handle(), "template/request.json")
 }
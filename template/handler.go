@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"os"
 	"time"
 
 	sdk "github.com/golanguzb70/ucode-sdk"
@@ -49,6 +50,11 @@ func Handle() http.HandlerFunc {
 		// set timeout for request
 		ucodeApi.Config().RequestTimeout = time.Duration(30 * time.Second)
 
+		// UCODE_BASE_URL is set by `serve` to point the function at a local backend
+		if url := os.Getenv("UCODE_BASE_URL"); url != "" {
+			ucodeApi.Config().SetBaseUrl(url)
+		}
+
 		// set app_id from .env file
 		ucodeApi.Config().AppId = ""
 
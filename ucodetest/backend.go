@@ -0,0 +1,639 @@
+/*
+Package ucodetest provides an in-memory uCode backend for tests and local development.
+
+The backend understands the endpoints used by ucodesdk, keeps objects per
+app id (the X-API-KEY header) and table slug and answers in the same shape as
+api.admin.u-code.io, so handlers and SDK helpers can run without network:
+
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	server.Seed("app_id", "houses", map[string]interface{}{"name": "house", "price": 15000})
+	ucodeApi := server.Client("app_id")
+
+A create or update sent again with the same Idempotency-Key header gets the
+first answer and changes nothing.
+*/
+package ucodetest
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/spf13/cast"
+)
+
+// Backend is an http.Handler storing uCode objects in memory.
+type Backend struct {
+	mu          sync.Mutex
+	apps        map[string]map[string][]map[string]interface{}
+	requests    []string
+	functions   map[string]FunctionHandler
+	invocations []Invocation
+	failWhen    func(method, path string, body []byte) bool
+	postgres    map[string]bool
+	// replies are the answers to creates and updates by app id and idempotency key
+	replies map[string]reply
+}
+
+// reply is a recorded answer, sent again for a request with the same idempotency key.
+type reply struct {
+	statusCode int
+	body       []byte
+}
+
+func NewBackend() *Backend {
+	return &Backend{
+		apps:      map[string]map[string][]map[string]interface{}{},
+		functions: map[string]FunctionHandler{},
+		postgres:  map[string]bool{},
+		replies:   map[string]reply{},
+	}
+}
+
+// UsePostgres makes the app answer like a Postgres app, the many-to-many and aggregation endpoints fail with 400.
+func (b *Backend) UsePostgres(appId string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.postgres[appId] = true
+}
+
+/*
+Seed inserts objects into the table of the app and returns their guids.
+Objects without a guid get a generated one.
+*/
+func (b *Backend) Seed(appId, tableSlug string, objects ...map[string]interface{}) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	guids := make([]string, 0, len(objects))
+	for _, object := range objects {
+		guids = append(guids, b.insert(appId, tableSlug, object))
+	}
+
+	return guids
+}
+
+// Objects returns a copy of the objects stored in the table of the app in insertion order.
+func (b *Backend) Objects(appId, tableSlug string) []map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rows := b.apps[appId][tableSlug]
+	objects := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		objects = append(objects, copyObject(row))
+	}
+
+	return objects
+}
+
+// Requests returns every request served so far as "METHOD /path".
+func (b *Backend) Requests() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]string(nil), b.requests...)
+}
+
+/*
+FailWhen makes the backend answer with status 500, without touching any object,
+every request for which fail returns true. A nil fail turns failures off.
+*/
+func (b *Backend) FailWhen(fail func(method, path string, body []byte) bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failWhen = fail
+}
+
+// Reset removes all objects and recorded requests.
+func (b *Backend) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.apps = map[string]map[string][]map[string]interface{}{}
+	b.requests = nil
+	b.invocations = nil
+	b.replies = map[string]reply{}
+}
+
+func (b *Backend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		appId = r.Header.Get("X-API-KEY")
+		parts = strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	)
+
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		reader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		defer reader.Close()
+		r.Body = reader
+	}
+
+	// functions run without the lock, they usually call the backend back
+	if len(parts) == 2 && (parts[0] == "function" || parts[0] == "async-function") {
+		b.mu.Lock()
+		b.requests = append(b.requests, r.Method+" "+r.URL.Path)
+		b.mu.Unlock()
+
+		b.invoke(w, r, parts[1], parts[0] == "async-function")
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.requests = append(b.requests, r.Method+" "+r.URL.Path)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if b.failWhen != nil && b.failWhen(r.Method, r.URL.Path, body) {
+		writeError(w, http.StatusInternalServerError, "failure injected by FailWhen")
+		return
+	}
+
+	// a create or update sent again with its idempotency key gets the first answer and changes nothing
+	if key := r.Header.Get(ucodesdk.IdempotencyKeyHeader); key != "" && (r.Method == http.MethodPost || r.Method == http.MethodPut) {
+		key = appId + "\x00" + key
+		if reply, ok := b.replies[key]; ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(reply.statusCode)
+			w.Write(reply.body)
+			return
+		}
+
+		recorder := httptest.NewRecorder()
+		defer func(w http.ResponseWriter) {
+			if recorder.Code < http.StatusBadRequest {
+				b.replies[key] = reply{statusCode: recorder.Code, body: recorder.Body.Bytes()}
+			}
+			for name, values := range recorder.Header() {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(recorder.Code)
+			w.Write(recorder.Body.Bytes())
+		}(w)
+		w = recorder
+	}
+
+	switch {
+	// /v2/items/many-to-many
+	case len(parts) == 3 && parts[0] == "v2" && parts[1] == "items" && parts[2] == "many-to-many":
+		if b.postgres[appId] {
+			writeError(w, http.StatusBadRequest, "many-to-many is not supported on postgres")
+			return
+		}
+		b.manyToMany(w, r.Method, appId, body)
+
+	// /v2/items/{table}
+	case len(parts) == 3 && parts[0] == "v2" && parts[1] == "items":
+		switch r.Method {
+		case http.MethodPost:
+			b.create(w, appId, parts[2], body)
+		case http.MethodPut:
+			b.update(w, appId, parts[2], body)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, r.Method)
+		}
+
+	// /v2/items/{table}/aggregation
+	case len(parts) == 4 && parts[0] == "v2" && parts[1] == "items" && parts[3] == "aggregation":
+		if b.postgres[appId] {
+			writeError(w, http.StatusBadRequest, "aggregation is not supported")
+			return
+		}
+		b.aggregation(w, appId, parts[2], body)
+
+	// /v2/items/{table}/{guid}
+	case len(parts) == 4 && parts[0] == "v2" && parts[1] == "items":
+		switch r.Method {
+		case http.MethodGet:
+			b.single(w, appId, parts[2], parts[3], nil)
+		case http.MethodDelete:
+			b.delete(w, appId, parts[2], []string{parts[3]})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, r.Method)
+		}
+
+	// /v1/object-slim/{table}/{guid}
+	case len(parts) == 4 && parts[0] == "v1" && parts[1] == "object-slim":
+		data := map[string]interface{}{}
+		if query := r.URL.Query().Get("data"); query != "" {
+			if err = json.Unmarshal([]byte(query), &data); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		b.single(w, appId, parts[2], parts[3], data)
+
+	// /v2/object/get-list/{table}
+	case len(parts) == 4 && parts[0] == "v2" && parts[1] == "object" && parts[2] == "get-list":
+		var request struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		if err = json.Unmarshal(body, &request); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		b.list(w, appId, parts[3], request.Data, cast.ToInt(request.Data["offset"]), cast.ToInt(request.Data["limit"]))
+
+	// /v2/object-slim/get-list/{table}
+	case len(parts) == 4 && parts[0] == "v2" && parts[1] == "object-slim" && parts[2] == "get-list":
+		var (
+			query  = r.URL.Query()
+			filter = map[string]interface{}{}
+		)
+		if data := query.Get("data"); data != "" {
+			if err = json.Unmarshal([]byte(data), &filter); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		b.list(w, appId, parts[3], filter, cast.ToInt(query.Get("offset")), cast.ToInt(query.Get("limit")))
+
+	// /v1/object/multiple-update/{table}
+	case len(parts) == 4 && parts[0] == "v1" && parts[1] == "object" && parts[2] == "multiple-update":
+		b.multipleUpdate(w, appId, parts[3], body)
+
+	// /v1/object/{table}
+	case len(parts) == 3 && parts[0] == "v1" && parts[1] == "object" && r.Method == http.MethodDelete:
+		var request struct {
+			Ids []string `json:"ids"`
+		}
+		if err = json.Unmarshal(body, &request); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		b.delete(w, appId, parts[2], request.Ids)
+
+	default:
+		writeError(w, http.StatusNotFound, "route not found: "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func (b *Backend) create(w http.ResponseWriter, appId, tableSlug string, body []byte) {
+	var request struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if request.Data == nil {
+		writeError(w, http.StatusBadRequest, "data is required")
+		return
+	}
+
+	guid := b.insert(appId, tableSlug, request.Data)
+	object, _ := b.find(appId, tableSlug, guid)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"status": "CREATED",
+		"data":   map[string]interface{}{"data": map[string]interface{}{"data": copyObject(object)}},
+	})
+}
+
+func (b *Backend) update(w http.ResponseWriter, appId, tableSlug string, body []byte) {
+	var request struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	object, ok := b.find(appId, tableSlug, cast.ToString(request.Data["guid"]))
+	if !ok {
+		writeError(w, http.StatusNotFound, "object not found")
+		return
+	}
+
+	for key, value := range request.Data {
+		object[key] = value
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "OK",
+		"data":   map[string]interface{}{"table_slug": tableSlug, "data": copyObject(object)},
+	})
+}
+
+func (b *Backend) multipleUpdate(w http.ResponseWriter, appId, tableSlug string, body []byte) {
+	var request struct {
+		Data struct {
+			Objects []map[string]interface{} `json:"objects"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// check every object first, the whole batch fails like a single transaction
+	for _, changes := range request.Data.Objects {
+		_, ok := b.find(appId, tableSlug, cast.ToString(changes["guid"]))
+		isNew := cast.ToBool(changes["is_new"])
+		if !ok && !isNew {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("object not found: %v", changes["guid"]))
+			return
+		}
+		if ok && isNew {
+			writeError(w, http.StatusConflict, fmt.Sprintf("object already exists: %v", changes["guid"]))
+			return
+		}
+	}
+
+	// objects marked with is_new are created
+	objects := make([]map[string]interface{}, 0, len(request.Data.Objects))
+	for _, changes := range request.Data.Objects {
+		object, ok := b.find(appId, tableSlug, cast.ToString(changes["guid"]))
+		if !ok {
+			created := copyObject(changes)
+			delete(created, "is_new")
+			object, _ = b.find(appId, tableSlug, b.insert(appId, tableSlug, created))
+		}
+
+		for key, value := range changes {
+			if key != "is_new" {
+				object[key] = value
+			}
+		}
+		objects = append(objects, copyObject(object))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "OK",
+		"data":   map[string]interface{}{"data": map[string]interface{}{"objects": objects}},
+	})
+}
+
+func (b *Backend) single(w http.ResponseWriter, appId, tableSlug, guid string, data map[string]interface{}) {
+	object, ok := b.find(appId, tableSlug, guid)
+	if !ok {
+		writeError(w, http.StatusNotFound, "object not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "OK",
+		"data":   map[string]interface{}{"data": map[string]interface{}{"response": b.withRelations(appId, copyObject(object), data)}},
+	})
+}
+
+/*
+withRelations sets <relation>_id_data of the object to the related object, when
+data has "with_relations": true, for the "selected_relations" or every <relation>_id field.
+*/
+func (b *Backend) withRelations(appId string, object, data map[string]interface{}) map[string]interface{} {
+	if !cast.ToBool(data["with_relations"]) {
+		return object
+	}
+
+	relations := cast.ToStringSlice(data["selected_relations"])
+	if len(relations) == 0 {
+		for field := range object {
+			if strings.HasSuffix(field, "_id") {
+				relations = append(relations, strings.TrimSuffix(field, "_id"))
+			}
+		}
+	}
+
+	for _, relation := range relations {
+		if related, ok := b.find(appId, relation, cast.ToString(object[relation+"_id"])); ok {
+			object[relation+"_id_data"] = copyObject(related)
+		}
+	}
+
+	return object
+}
+
+func (b *Backend) list(w http.ResponseWriter, appId, tableSlug string, filter map[string]interface{}, offset, limit int) {
+	var matched []map[string]interface{}
+	for _, row := range b.apps[appId][tableSlug] {
+		if matches(row, filter) {
+			matched = append(matched, row)
+		}
+	}
+
+	count := len(matched)
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	matched = matched[offset:]
+	if limit > 0 && limit < len(matched) {
+		matched = matched[:limit]
+	}
+
+	response := make([]map[string]interface{}, 0, len(matched))
+	for _, row := range matched {
+		response = append(response, b.withRelations(appId, copyObject(row), filter))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "OK",
+		"data":   map[string]interface{}{"data": map[string]interface{}{"count": count, "response": response}},
+	})
+}
+
+// aggregation supports the $match, $skip and $limit stages.
+func (b *Backend) aggregation(w http.ResponseWriter, appId, tableSlug string, body []byte) {
+	var request struct {
+		Data struct {
+			Pipelines []map[string]interface{} `json:"pipelines"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows := b.apps[appId][tableSlug]
+	for _, stage := range request.Data.Pipelines {
+		for operator, argument := range stage {
+			switch operator {
+			case "$match":
+				var matched []map[string]interface{}
+				for _, row := range rows {
+					if matches(row, cast.ToStringMap(argument)) {
+						matched = append(matched, row)
+					}
+				}
+				rows = matched
+			case "$skip":
+				skip := cast.ToInt(argument)
+				if skip > len(rows) {
+					skip = len(rows)
+				}
+				rows = rows[skip:]
+			case "$limit":
+				if limit := cast.ToInt(argument); limit < len(rows) {
+					rows = rows[:limit]
+				}
+			default:
+				writeError(w, http.StatusBadRequest, "unsupported aggregation stage: "+operator)
+				return
+			}
+		}
+	}
+
+	data := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		data = append(data, copyObject(row))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "OK",
+		"data":   map[string]interface{}{"data": map[string]interface{}{"data": data}},
+	})
+}
+
+func (b *Backend) delete(w http.ResponseWriter, appId, tableSlug string, guids []string) {
+	remove := map[string]bool{}
+	for _, guid := range guids {
+		remove[guid] = true
+	}
+
+	table := b.apps[appId][tableSlug]
+	kept := table[:0]
+	for _, row := range table {
+		if !remove[cast.ToString(row["guid"])] {
+			kept = append(kept, row)
+		}
+	}
+	if b.apps[appId] != nil {
+		b.apps[appId][tableSlug] = kept
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "NO_CONTENT"})
+}
+
+// manyToMany appends (PUT) or removes (DELETE) id_to in the <table_to>_ids field of the id_from object.
+func (b *Backend) manyToMany(w http.ResponseWriter, method, appId string, body []byte) {
+	var request struct {
+		TableFrom string      `json:"table_from"`
+		TableTo   string      `json:"table_to"`
+		IdFrom    string      `json:"id_from"`
+		IdTo      interface{} `json:"id_to"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	object, ok := b.find(appId, request.TableFrom, request.IdFrom)
+	if !ok {
+		writeError(w, http.StatusNotFound, "object not found")
+		return
+	}
+
+	var (
+		field   = request.TableTo + "_ids"
+		current = cast.ToStringSlice(object[field])
+		ids     = cast.ToStringSlice(request.IdTo)
+	)
+
+	switch method {
+	case http.MethodPut:
+		for _, id := range ids {
+			if !contains(current, id) {
+				current = append(current, id)
+			}
+		}
+	case http.MethodDelete:
+		kept := []string{}
+		for _, id := range current {
+			if !contains(ids, id) {
+				kept = append(kept, id)
+			}
+		}
+		current = kept
+	default:
+		writeError(w, http.StatusMethodNotAllowed, method)
+		return
+	}
+
+	object[field] = current
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "OK"})
+}
+
+func (b *Backend) insert(appId, tableSlug string, data map[string]interface{}) string {
+	object := copyObject(data)
+
+	guid := cast.ToString(object["guid"])
+	if guid == "" {
+		guid = ucodesdk.NewGUID()
+		object["guid"] = guid
+	}
+
+	if b.apps[appId] == nil {
+		b.apps[appId] = map[string][]map[string]interface{}{}
+	}
+	b.apps[appId][tableSlug] = append(b.apps[appId][tableSlug], object)
+
+	return guid
+}
+
+func (b *Backend) find(appId, tableSlug, guid string) (map[string]interface{}, bool) {
+	for _, row := range b.apps[appId][tableSlug] {
+		if cast.ToString(row["guid"]) == guid {
+			return row, true
+		}
+	}
+
+	return nil, false
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"status":      http.StatusText(statusCode),
+		"description": message,
+		"data":        message,
+	})
+}
+
+// copyObject round trips the object through JSON so stored rows never share memory with callers.
+func copyObject(object map[string]interface{}) map[string]interface{} {
+	var copied map[string]interface{}
+
+	objectByte, _ := json.Marshal(object)
+	_ = json.Unmarshal(objectByte, &copied)
+
+	if copied == nil {
+		copied = map[string]interface{}{}
+	}
+
+	return copied
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
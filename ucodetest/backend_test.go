@@ -0,0 +1,95 @@
+package ucodetest
+
+import (
+	"context"
+	"testing"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/spf13/cast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackend(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId)
+		roomIds  = server.Seed(appId, "room", map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"})
+	)
+
+	created, _, err := ucodeApi.CreateObject(&ucodesdk.Argument{
+		TableSlug: "houses",
+		Request:   ucodesdk.Request{Data: map[string]interface{}{"name": "house", "price": 15000}},
+	})
+	assert.NoError(t, err)
+
+	houseId := cast.ToString(created.Data.Data.Data["guid"])
+	assert.NotEmpty(t, houseId)
+
+	_, err = ucodeApi.AppendManyToMany(&ucodesdk.Argument{
+		TableSlug: "houses",
+		Request: ucodesdk.Request{Data: map[string]interface{}{
+			"table_from": "houses",
+			"table_to":   "room",
+			"id_from":    houseId,
+			"id_to":      roomIds,
+		}},
+	})
+	assert.NoError(t, err)
+
+	house, _, err := ucodeApi.GetSingleSlim(&ucodesdk.Argument{
+		TableSlug: "houses",
+		Request:   ucodesdk.Request{Data: map[string]interface{}{"guid": houseId}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, roomIds, cast.ToStringSlice(house.Data.Data.Response["room_ids"]))
+
+	list, _, err := ucodeApi.GetListSlim(&ucodesdk.ArgumentWithPegination{
+		TableSlug: "room",
+		Request:   ucodesdk.Request{Data: map[string]interface{}{"name": "b"}},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Data.Data.Response, 1) {
+		assert.Equal(t, roomIds[1], list.Data.Data.Response[0]["guid"])
+	}
+
+	_, err = ucodeApi.MultipleDelete(&ucodesdk.Argument{
+		TableSlug: "room",
+		Request:   ucodesdk.Request{Data: map[string]interface{}{"ids": roomIds}},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, server.Objects(appId, "room"))
+
+	// objects are kept per app
+	assert.Empty(t, server.Objects("other_app_id", "houses"))
+}
+
+func TestBackendIdempotency(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId)
+		create   = func(key string) error {
+			ctx := ucodesdk.WithIdempotencyKey(context.Background(), key)
+			_, err := ucodeApi.Bulk().CreateMany(ctx, "houses", []map[string]interface{}{{"guid": "house-1", "name": "house"}}, nil)
+			return err
+		}
+	)
+
+	assert.NoError(t, create("create-1"))
+	// the same key gets the first answer
+	assert.NoError(t, create("create-1"))
+	assert.Len(t, server.Objects(appId, "houses"), 1)
+
+	// another key creates the object again
+	err := create("create-2")
+	var httpErr *ucodesdk.HTTPError
+	if assert.ErrorAs(t, err, &httpErr) {
+		assert.Equal(t, 409, httpErr.StatusCode)
+	}
+	assert.Len(t, server.Objects(appId, "houses"), 1)
+}
@@ -0,0 +1,129 @@
+package ucodetest
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cast"
+)
+
+// reservedKeys are request keys which are options, not field filters.
+var reservedKeys = map[string]bool{
+	"offset":             true,
+	"limit":              true,
+	"order":              true,
+	"search":             true,
+	"view_fields":        true,
+	"with_relations":     true,
+	"selected_relations": true,
+	"is_cached":          true,
+}
+
+/*
+matches reports whether the row satisfies every field filter.
+
+A scalar filter matches equal values and array fields containing it,
+an array filter works like $in and a map filter may use the
+$eq, $ne, $in, $nin, $gt, $gte, $lt and $lte operators.
+*/
+func matches(row map[string]interface{}, filter map[string]interface{}) bool {
+	for field, condition := range filter {
+		if reservedKeys[field] {
+			continue
+		}
+		if !matchField(row[field], condition) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchField(value, condition interface{}) bool {
+	switch condition := condition.(type) {
+	case map[string]interface{}:
+		for operator, argument := range condition {
+			if !matchOperator(value, operator, argument) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		return matchOperator(value, "$in", condition)
+	default:
+		return matchOperator(value, "$eq", condition)
+	}
+}
+
+func matchOperator(value interface{}, operator string, argument interface{}) bool {
+	switch operator {
+	case "$eq":
+		if values, ok := value.([]interface{}); ok {
+			for _, v := range values {
+				if equal(v, argument) {
+					return true
+				}
+			}
+			return false
+		}
+		return equal(value, argument)
+	case "$ne":
+		return !matchOperator(value, "$eq", argument)
+	case "$in":
+		for _, candidate := range cast.ToSlice(argument) {
+			if matchOperator(value, "$eq", candidate) {
+				return true
+			}
+		}
+		return false
+	case "$nin":
+		return !matchOperator(value, "$in", argument)
+	case "$gt":
+		return compare(value, argument) > 0
+	case "$gte":
+		return compare(value, argument) >= 0
+	case "$lt":
+		return value != nil && compare(value, argument) < 0
+	case "$lte":
+		return value != nil && compare(value, argument) <= 0
+	default:
+		return false
+	}
+}
+
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func compare(a, b interface{}) int {
+	fa, errA := cast.ToFloat64E(a)
+	fb, errB := cast.ToFloat64E(b)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	sa, sb := cast.ToString(a), cast.ToString(b)
+	switch {
+	case sa < sb:
+		return -1
+	case sa > sb:
+		return 1
+	default:
+		return 0
+	}
+}
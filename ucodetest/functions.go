@@ -0,0 +1,99 @@
+package ucodetest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+)
+
+type (
+	// FunctionHandler fakes a uCode function invoked through the backend.
+	FunctionHandler func(ctx context.Context, data ucodesdk.Data) (ucodesdk.Response, error)
+
+	// Invocation is a recorded call of a function.
+	Invocation struct {
+		Name          string
+		Async         bool
+		Data          ucodesdk.Data
+		CorrelationId string
+		Caller        string
+	}
+)
+
+/*
+HandleFunction registers a fake function served on /function/<name> and /async-function/<name>.
+
+An error returned by the handler is answered with status 500 and a Response of status "error".
+The context of the handler carries the correlation id of the invocation.
+*/
+func (b *Backend) HandleFunction(name string, handler FunctionHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.functions[name] = handler
+}
+
+// Invocations returns the recorded calls of the function in order.
+func (b *Backend) Invocations(name string) []Invocation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var invocations []Invocation
+	for _, invocation := range b.invocations {
+		if invocation.Name == name {
+			invocations = append(invocations, invocation)
+		}
+	}
+
+	return invocations
+}
+
+func (b *Backend) invoke(w http.ResponseWriter, r *http.Request, name string, async bool) {
+	var request ucodesdk.FunctionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invocation := Invocation{
+		Name:          name,
+		Async:         async,
+		Data:          request.Data,
+		CorrelationId: r.Header.Get(ucodesdk.CorrelationIdHeader),
+		Caller:        r.Header.Get("X-Caller-Function"),
+	}
+
+	b.mu.Lock()
+	handler, ok := b.functions[name]
+	b.invocations = append(b.invocations, invocation)
+	b.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "function not found: "+name)
+		return
+	}
+
+	ctx := ucodesdk.WithCorrelationId(context.Background(), invocation.CorrelationId)
+
+	if async {
+		go func() { _, _ = handler(ctx, request.Data) }()
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	response, err := handler(ctx, request.Data)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, ucodesdk.Response{
+			Status: "error",
+			Data:   map[string]interface{}{"message": "Error on handling request", "error": err.Error()},
+		})
+		return
+	}
+
+	if response.Status == "" {
+		response.Status = "done"
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,57 @@
+package ucodetest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+)
+
+// Server is a Backend listening on a local port.
+type Server struct {
+	*Backend
+
+	// URL is the base url of the server, use it as Config.BaseURL.
+	URL string
+
+	server *httptest.Server
+}
+
+func NewServer() *Server {
+	backend := NewBackend()
+	server := httptest.NewServer(backend)
+
+	return &Server{
+		Backend: backend,
+		URL:     server.URL,
+		server:  server,
+	}
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns an SDK client sending requests of the app to the server.
+func (s *Server) Client(appId string) ucodesdk.UcodeApis {
+	return ucodesdk.New(&ucodesdk.Config{BaseURL: s.URL, FunctionsURL: s.URL, AppId: appId})
+}
+
+/*
+LoadSeed seeds the app from JSON keyed by table slug:
+
+	{"houses": [{"name": "house", "price": 15000}], "room": [...]}
+*/
+func (b *Backend) LoadSeed(appId string, r io.Reader) error {
+	var tables map[string][]map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&tables); err != nil {
+		return err
+	}
+
+	for tableSlug, objects := range tables {
+		b.Seed(appId, tableSlug, objects...)
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Actions taken by Upsert.
+const (
+	UpsertCreated = "created"
+	UpsertUpdated = "updated"
+)
+
+var (
+	// ErrDuplicateKey is returned when the key fields match more than one object.
+	ErrDuplicateKey = errors.New("key fields match more than one object")
+	// ErrMissingKey is returned when an object has no value for one of the key fields.
+	ErrMissingKey = errors.New("object has no value for a key field")
+)
+
+// UpsertResult is the outcome of one object of Upsert or UpsertMany.
+type UpsertResult struct {
+	// Index is the position of the object in the input.
+	Index int
+	Guid  string
+	// Action is UpsertCreated or UpsertUpdated.
+	Action string
+	Err    error
+}
+
+func (o *object) Upsert(ctx context.Context, tableSlug string, keyFields []string, object map[string]interface{}, opts *BulkOptions) (UpsertResult, error) {
+	results, err := o.UpsertMany(ctx, tableSlug, keyFields, []map[string]interface{}{object}, opts)
+	if len(results) == 0 {
+		return UpsertResult{}, err
+	}
+
+	return results[0], results[0].Err
+}
+
+func (o *object) UpsertMany(ctx context.Context, tableSlug string, keyFields []string, objects []map[string]interface{}, opts *BulkOptions) ([]UpsertResult, error) {
+	if len(keyFields) == 0 {
+		return nil, errors.New("at least one key field is required")
+	}
+
+	var (
+		options = opts.withDefaults()
+		results = make([]UpsertResult, len(objects))
+		seen    = map[string]int{}
+	)
+
+	for i, object := range objects {
+		results[i] = UpsertResult{Index: i}
+
+		key, err := upsertKey(object, keyFields)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		// two objects of the input with the same key would both be created
+		if first, ok := seen[key]; ok {
+			results[i].Err = fmt.Errorf("%w: same key as object %d of the input", ErrDuplicateKey, first)
+			continue
+		}
+		seen[key] = i
+	}
+
+	o.lookupKeys(ctx, tableSlug, keyFields, objects, options, results)
+
+	var (
+		creates, updates             []map[string]interface{}
+		createIndexes, updateIndexes []int
+	)
+	for i, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		switch result.Action {
+		case UpsertUpdated:
+			object := make(map[string]interface{}, len(objects[i])+1)
+			for key, value := range objects[i] {
+				object[key] = value
+			}
+			object["guid"] = result.Guid
+
+			updates = append(updates, object)
+			updateIndexes = append(updateIndexes, i)
+		default:
+			creates = append(creates, objects[i])
+			createIndexes = append(createIndexes, i)
+		}
+	}
+
+	if len(creates) > 0 {
+		created, _ := o.CreateMany(ctx, tableSlug, creates, &options)
+		for i, result := range created {
+			results[createIndexes[i]].Guid = result.Guid
+			results[createIndexes[i]].Err = result.Err
+		}
+	}
+
+	if len(updates) > 0 {
+		updated, _ := o.UpdateMany(ctx, tableSlug, updates, &options)
+		for i, result := range updated {
+			results[updateIndexes[i]].Err = result.Err
+		}
+	}
+
+	var (
+		failed   int
+		firstErr error
+	)
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = result.Err
+			}
+		}
+	}
+	if failed > 0 {
+		return results, &BulkError{Total: len(results), Failed: failed, Err: firstErr}
+	}
+
+	return results, nil
+}
+
+// lookupKeys finds the existing object of every pending result and sets its action, options.Concurrency lookups at once.
+func (o *object) lookupKeys(ctx context.Context, tableSlug string, keyFields []string, objects []map[string]interface{}, options BulkOptions, results []UpsertResult) {
+	var (
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, options.Concurrency)
+	)
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+
+		semaphore <- struct{}{}
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			filter := make(map[string]interface{}, len(keyFields))
+			for _, field := range keyFields {
+				filter[field] = objects[i][field]
+			}
+
+			// two rows are enough to find out the key is not unique
+			rows, err := o.listSlim(ctx, options.AppId, tableSlug, filter, 0, 2, options.DisableFaas)
+			switch {
+			case err != nil:
+				results[i].Err = err
+			case len(rows) > 1:
+				results[i].Err = ErrDuplicateKey
+			case len(rows) == 1:
+				results[i].Action = UpsertUpdated
+				results[i].Guid = fmt.Sprint(rows[0]["guid"])
+			default:
+				results[i].Action = UpsertCreated
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// upsertKey returns the values of the key fields of the object as a comparable string.
+func upsertKey(object map[string]interface{}, keyFields []string) (string, error) {
+	fields := append([]string(nil), keyFields...)
+	sort.Strings(fields)
+
+	values := make([]string, 0, len(fields))
+	for _, field := range fields {
+		value, ok := object[field]
+		if !ok || value == nil {
+			return "", fmt.Errorf("%w: %s", ErrMissingKey, field)
+		}
+
+		valueByte, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, field+"="+string(valueByte))
+	}
+
+	return strings.Join(values, "&"), nil
+}
+
+// listSlim is GetListSlim bound to ctx which checks the status code and returns the objects only.
+func (o *object) listSlim(ctx context.Context, appId, tableSlug string, filter map[string]interface{}, offset, limit int, disableFaas bool) ([]map[string]interface{}, error) {
+	var listSlim GetListClientApiResponse
+
+	filterByte, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	listUrl := fmt.Sprintf("%s/v2/object-slim/get-list/%s?from-ofs=%t&data=%s&offset=%d&limit=%d",
+		o.config.BaseURL, tableSlug, disableFaas, escapeQuery(filterByte), offset, limit)
+
+	statusCode, respByte, err := o.send(ctx, listUrl, http.MethodGet, nil, o.headers(appId))
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode >= http.StatusBadRequest {
+		return nil, &HTTPError{StatusCode: statusCode, Body: respByte}
+	}
+
+	if err = json.Unmarshal(respByte, &listSlim); err != nil {
+		return nil, err
+	}
+
+	return listSlim.Data.Data.Response, nil
+}
+
+// escapeQuery escapes a JSON filter for the data query parameter.
+func escapeQuery(filter []byte) string {
+	return url.QueryEscape(string(filter))
+}
@@ -0,0 +1,417 @@
+package ucodesdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type (
+	// PageInfo describes the page returned by a list call.
+	PageInfo struct {
+		Page   int
+		Limit  int
+		Offset int
+		// Total is the number of objects matching the filter, -1 when the server does not send it.
+		Total int
+		// HasNext reports whether there are objects after the page. Without Total, it is set when the page is full.
+		HasNext bool
+	}
+
+	// Option configures a call of V2Apis.
+	Option func(*callOptions)
+
+	callOptions struct {
+		appId          string
+		disableFaas    bool
+		page, limit    int
+		cached         bool
+		noCoalescing   bool
+		relations      []string
+		relationFields map[string][]string
+	}
+
+	/*
+		V2Apis has the methods of UcodeApis returning the objects directly.
+		They take a context, check the status code of the response and
+		return *HTTPError when it is not successful.
+	*/
+	V2Apis interface {
+		/*
+			CreateObject is a function that creates new object and returns it
+
+			The idempotency key of ctx (see WithIdempotencyKey) is sent, a new one when there is none.
+
+			Works for [Mongo, Postgres]
+		*/
+		CreateObject(ctx context.Context, tableSlug string, data map[string]interface{}, opts ...Option) (Object, error)
+		/*
+			GetSingle is a function that gets the object with the guid, ErrNotFound when there is none
+
+			Works for [Mongo, Postgres]
+		*/
+		GetSingle(ctx context.Context, tableSlug, guid string, opts ...Option) (Object, error)
+		/*
+			GetSingleSlim is a function that gets the object with the guid like GetSingle, without the relations
+			not selected with WithRelations
+
+			Works for [Mongo, Postgres]
+		*/
+		GetSingleSlim(ctx context.Context, tableSlug, guid string, opts ...Option) (Object, error)
+		/*
+			GetList is a function that gets a page of the objects matching the filter, see WithPage
+
+			Works for [Mongo, Postgres]
+		*/
+		GetList(ctx context.Context, tableSlug string, filter map[string]interface{}, opts ...Option) ([]Object, PageInfo, error)
+		/*
+			GetListSlim is a function that gets a page of the objects matching the filter like GetList, without the relations
+			not selected with WithRelations
+
+			Works for [Mongo, Postgres]
+		*/
+		GetListSlim(ctx context.Context, tableSlug string, filter map[string]interface{}, opts ...Option) ([]Object, PageInfo, error)
+		/*
+			Count is a function that returns the number of objects matching the filter. When the server
+			does not send the total, the objects are counted page by page.
+
+			Works for [Mongo, Postgres]
+		*/
+		Count(ctx context.Context, tableSlug string, filter map[string]interface{}, opts ...Option) (int, error)
+		/*
+			GetListAggregation is a function that runs the aggregation pipelines on the table and returns the result rows,
+			ErrUnsupportedOnBackend on Postgres apps
+
+			Works for [Mongo]
+		*/
+		GetListAggregation(ctx context.Context, tableSlug string, pipelines []map[string]interface{}, opts ...Option) ([]Object, error)
+		/*
+			UpdateObject is a function that updates the object with the guid in data and returns it
+
+			Works for [Mongo, Postgres]
+		*/
+		UpdateObject(ctx context.Context, tableSlug string, data map[string]interface{}, opts ...Option) (Object, error)
+		/*
+			MultipleUpdate is a function that updates objects, or creates the ones with "is_new": true, and returns them
+
+			Works for [Mongo, Postgres]
+		*/
+		MultipleUpdate(ctx context.Context, tableSlug string, objects []map[string]interface{}, opts ...Option) ([]Object, error)
+		/*
+			Delete is a function that deletes the object with the guid
+
+			Works for [Mongo, Postgres]
+		*/
+		Delete(ctx context.Context, tableSlug, guid string, opts ...Option) error
+		/*
+			MultipleDelete is a function that deletes the objects with the guids
+
+			Works for [Mongo, Postgres]
+		*/
+		MultipleDelete(ctx context.Context, tableSlug string, ids []string, opts ...Option) error
+	}
+
+	v2 struct {
+		o *object
+	}
+)
+
+// WithAppId sends the call to the app instead of the app of the config.
+func WithAppId(appId string) Option {
+	return func(opts *callOptions) { opts.appId = appId }
+}
+
+// WithDisableFaas does not run the functions of the table for the call.
+func WithDisableFaas() Option {
+	return func(opts *callOptions) { opts.disableFaas = true }
+}
+
+// WithPage selects the page of a list call, page 1 of 10 objects by default.
+func WithPage(page, limit int) Option {
+	return func(opts *callOptions) { opts.page, opts.limit = page, limit }
+}
+
+// WithCache sets Request.IsCached, see Config.Cache.
+func WithCache() Option {
+	return func(opts *callOptions) { opts.cached = true }
+}
+
+// WithoutCoalescing sends a read even when an identical one is in flight.
+func WithoutCoalescing() Option {
+	return func(opts *callOptions) { opts.noCoalescing = true }
+}
+
+func newCallOptions(opts []Option) callOptions {
+	var options callOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+func (o *object) V2() V2Apis {
+	return &v2{o: o}
+}
+
+func (v *v2) CreateObject(ctx context.Context, tableSlug string, data map[string]interface{}, opts ...Option) (Object, error) {
+	var (
+		options = newCallOptions(opts)
+		url     = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t", v.o.config.BaseURL, tableSlug, options.disableFaas)
+		arg     = &Argument{AppId: options.appId, TableSlug: tableSlug, Request: Request{Data: data}, DisableFaas: options.disableFaas}
+		created Datas
+	)
+
+	defer v.o.invalidate(options.appId, tableSlug)
+
+	var existing map[string]interface{}
+	statusCode, respByte, err := v.o.sendIdempotent(ctx, url, http.MethodPost, arg.Request, v.o.headers(options.appId), IdempotencyKey(ctx), v.o.createLookup(arg, arg.AppId, &existing))
+	if existing != nil {
+		return existing, nil
+	}
+
+	if err = decodeResponse(statusCode, respByte, err, &created); err != nil {
+		return nil, err
+	}
+
+	return created.Item(), nil
+}
+
+func (v *v2) GetSingle(ctx context.Context, tableSlug, guid string, opts ...Option) (Object, error) {
+	options := newCallOptions(opts)
+	return v.getSingle(ctx, tableSlug, guid, fmt.Sprintf("%s/v2/items/%s/%s?from-ofs=%t", v.o.config.BaseURL, tableSlug, guid, options.disableFaas), options)
+}
+
+func (v *v2) GetSingleSlim(ctx context.Context, tableSlug, guid string, opts ...Option) (Object, error) {
+	var (
+		options = newCallOptions(opts)
+		url     = fmt.Sprintf("%s/v1/object-slim/%s/%s?from-ofs=%t", v.o.config.BaseURL, tableSlug, guid, options.disableFaas)
+	)
+
+	if data := options.relationData(nil); data != nil {
+		dataByte, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		url += "&data=" + escapeQuery(dataByte)
+	}
+
+	single, err := v.getSingle(ctx, tableSlug, guid, url, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return single, v.expandRelations(ctx, []Object{single}, options)
+}
+
+func (v *v2) getSingle(ctx context.Context, tableSlug, guid, url string, options callOptions) (Object, error) {
+	var single ClientApiResponse
+
+	statusCode, respByte, err := v.o.read(ctx, options.appId, tableSlug, url, http.MethodGet, nil, v.o.headers(options.appId), options.cached, !options.noCoalescing)
+	if err == nil && statusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s %s: %w", tableSlug, guid, ErrNotFound)
+	}
+
+	if err = decodeResponse(statusCode, respByte, err, &single); err != nil {
+		return nil, err
+	}
+
+	if len(single.Item()) == 0 {
+		return nil, fmt.Errorf("%s %s: %w", tableSlug, guid, ErrNotFound)
+	}
+
+	return single.Item(), nil
+}
+
+func (v *v2) GetList(ctx context.Context, tableSlug string, filter map[string]interface{}, opts ...Option) ([]Object, PageInfo, error) {
+	var (
+		options = newCallOptions(opts)
+		page    = options.pageInfo()
+		url     = fmt.Sprintf("%s/v2/object/get-list/%s?from-ofs=%t", v.o.config.BaseURL, tableSlug, options.disableFaas)
+		data    = make(map[string]interface{}, len(filter)+2)
+	)
+
+	for key, value := range filter {
+		data[key] = value
+	}
+	data["offset"] = page.Offset
+	data["limit"] = page.Limit
+
+	request := Request{Data: data, IsCached: options.cached}
+	statusCode, respByte, err := v.o.read(ctx, options.appId, tableSlug, url, http.MethodPost, request, v.o.headers(options.appId), options.cached, !options.noCoalescing)
+
+	return v.list(statusCode, respByte, err, page)
+}
+
+func (v *v2) GetListSlim(ctx context.Context, tableSlug string, filter map[string]interface{}, opts ...Option) ([]Object, PageInfo, error) {
+	var (
+		options = newCallOptions(opts)
+		page    = options.pageInfo()
+	)
+
+	filterByte, err := json.Marshal(options.relationData(filter))
+	if err != nil {
+		return nil, page, err
+	}
+
+	url := fmt.Sprintf("%s/v2/object-slim/get-list/%s?from-ofs=%t&data=%s&offset=%d&limit=%d",
+		v.o.config.BaseURL, tableSlug, options.disableFaas, escapeQuery(filterByte), page.Offset, page.Limit)
+
+	statusCode, respByte, err := v.o.read(ctx, options.appId, tableSlug, url, http.MethodGet, nil, v.o.headers(options.appId), options.cached, !options.noCoalescing)
+
+	objects, page, err := v.list(statusCode, respByte, err, page)
+	if err != nil {
+		return nil, page, err
+	}
+
+	return objects, page, v.expandRelations(ctx, objects, options)
+}
+
+func (v *v2) list(statusCode int, respByte []byte, err error, page PageInfo) ([]Object, PageInfo, error) {
+	var list GetListClientApiResponse
+	if err = decodeResponse(statusCode, respByte, err, &list); err != nil {
+		return nil, page, err
+	}
+
+	return list.Items(), page.complete(list), nil
+}
+
+func (v *v2) Count(ctx context.Context, tableSlug string, filter map[string]interface{}, opts ...Option) (int, error) {
+	objects, page, err := v.GetListSlim(ctx, tableSlug, filter, append(opts[:len(opts):len(opts)], WithPage(1, 1))...)
+	if err != nil {
+		return 0, err
+	}
+	if page.Total >= 0 {
+		return page.Total, nil
+	}
+	if len(objects) == 0 {
+		return 0, nil
+	}
+
+	count := 0
+	for number := 1; ; number++ {
+		objects, page, err = v.GetListSlim(ctx, tableSlug, filter, append(opts[:len(opts):len(opts)], WithPage(number, countPageSize))...)
+		if err != nil {
+			return 0, err
+		}
+
+		count += len(objects)
+		if !page.HasNext {
+			return count, nil
+		}
+	}
+}
+
+// countPageSize is the limit of the pages read by Count when the server does not send the total.
+const countPageSize = 500
+
+func (v *v2) GetListAggregation(ctx context.Context, tableSlug string, pipelines []map[string]interface{}, opts ...Option) ([]Object, error) {
+	var (
+		options     = newCallOptions(opts)
+		url         = fmt.Sprintf("%s/v2/items/%s/aggregation", v.o.config.BaseURL, tableSlug)
+		request     = Request{Data: map[string]interface{}{"pipelines": pipelines}, IsCached: options.cached}
+		aggregation GetListAggregationClientApiResponse
+	)
+
+	if err := v.o.supports(ctx, options.appId, "GetListAggregation", func(c Capabilities) bool { return c.Aggregation }); err != nil {
+		return nil, err
+	}
+
+	statusCode, respByte, err := v.o.read(ctx, options.appId, tableSlug, url, http.MethodPost, request, v.o.headers(options.appId), options.cached, !options.noCoalescing)
+	if err = decodeResponse(statusCode, respByte, err, &aggregation); err != nil {
+		return nil, err
+	}
+
+	return aggregation.Items(), nil
+}
+
+func (v *v2) UpdateObject(ctx context.Context, tableSlug string, data map[string]interface{}, opts ...Option) (Object, error) {
+	var (
+		options = newCallOptions(opts)
+		url     = fmt.Sprintf("%s/v2/items/%s?from-ofs=%t", v.o.config.BaseURL, tableSlug, options.disableFaas)
+		updated ClientApiUpdateResponse
+	)
+
+	defer v.o.invalidate(options.appId, tableSlug)
+
+	statusCode, respByte, err := v.o.sendIdempotent(ctx, url, http.MethodPut, Request{Data: data}, v.o.headers(options.appId), IdempotencyKey(ctx), nil)
+	if err = decodeResponse(statusCode, respByte, err, &updated); err != nil {
+		return nil, err
+	}
+
+	return updated.Item(), nil
+}
+
+func (v *v2) MultipleUpdate(ctx context.Context, tableSlug string, objects []map[string]interface{}, opts ...Option) ([]Object, error) {
+	var (
+		options = newCallOptions(opts)
+		url     = fmt.Sprintf("%s/v1/object/multiple-update/%s?from-ofs=%t", v.o.config.BaseURL, tableSlug, options.disableFaas)
+		updated ClientApiMultipleUpdateResponse
+	)
+
+	defer v.o.invalidate(options.appId, tableSlug)
+
+	statusCode, respByte, err := v.o.sendIdempotent(ctx, url, http.MethodPut, Request{Data: map[string]interface{}{"objects": objects}}, v.o.headers(options.appId), IdempotencyKey(ctx), nil)
+	if err = decodeResponse(statusCode, respByte, err, &updated); err != nil {
+		return nil, err
+	}
+
+	return updated.Items(), nil
+}
+
+func (v *v2) Delete(ctx context.Context, tableSlug, guid string, opts ...Option) error {
+	var (
+		options = newCallOptions(opts)
+		url     = fmt.Sprintf("%s/v2/items/%s/%s?from-ofs=%t", v.o.config.BaseURL, tableSlug, guid, options.disableFaas)
+	)
+
+	defer v.o.invalidate(options.appId, tableSlug)
+
+	statusCode, respByte, err := v.o.send(ctx, url, http.MethodDelete, Request{Data: map[string]interface{}{}}, v.o.headers(options.appId))
+	return decodeResponse(statusCode, respByte, err, nil)
+}
+
+func (v *v2) MultipleDelete(ctx context.Context, tableSlug string, ids []string, opts ...Option) error {
+	options := newCallOptions(opts)
+	return v.o.multipleDeleteChunk(ctx, tableSlug, ids, BulkOptions{AppId: options.appId, DisableFaas: options.disableFaas})
+}
+
+func (opts callOptions) pageInfo() PageInfo {
+	arg := ArgumentWithPegination{Page: opts.page, Limit: opts.limit}
+	return arg.pageInfo()
+}
+
+func (arg *ArgumentWithPegination) pageInfo() PageInfo {
+	offset, limit := arg.offsetLimit()
+	return PageInfo{Page: offset/limit + 1, Limit: limit, Offset: offset, Total: -1}
+}
+
+// complete sets Total and HasNext of the page from the list response.
+func (p PageInfo) complete(list GetListClientApiResponse) PageInfo {
+	if total, ok := list.Total(); ok {
+		p.Total = total
+		p.HasNext = p.Offset+list.Count() < total
+	} else {
+		p.HasNext = list.Count() >= p.Limit
+	}
+
+	return p
+}
+
+// decodeResponse checks the status code of a response and unmarshals it into out, when out is not nil.
+func decodeResponse(statusCode int, respByte []byte, err error, out interface{}) error {
+	if err != nil {
+		return err
+	}
+
+	if statusCode >= http.StatusBadRequest {
+		return &HTTPError{StatusCode: statusCode, Body: respByte}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.Unmarshal(respByte, out)
+}
@@ -0,0 +1,186 @@
+package ucodesdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	ucodesdk "github.com/golanguzb70/ucode-sdk"
+	"github.com/golanguzb70/ucode-sdk/ucodetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestV2(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId).V2()
+		ctx      = context.Background()
+	)
+
+	house, err := ucodeApi.CreateObject(ctx, "houses", map[string]interface{}{"name": "house", "price": 15000}, ucodesdk.WithDisableFaas())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, house.GUID())
+
+	for i := 0; i < 4; i++ {
+		_, err = ucodeApi.CreateObject(ctx, "houses", map[string]interface{}{"name": fmt.Sprintf("house_%d", i), "price": 20000})
+		assert.NoError(t, err)
+	}
+
+	single, err := ucodeApi.GetSingleSlim(ctx, "houses", house.GUID())
+	assert.NoError(t, err)
+	assert.Equal(t, 15000, single.Int("price"))
+
+	_, err = ucodeApi.GetSingle(ctx, "houses", "missing")
+	assert.ErrorIs(t, err, ucodesdk.ErrNotFound)
+
+	houses, page, err := ucodeApi.GetListSlim(ctx, "houses", map[string]interface{}{"price": 20000}, ucodesdk.WithPage(2, 3))
+	assert.NoError(t, err)
+	assert.Len(t, houses, 1)
+	assert.Equal(t, ucodesdk.PageInfo{Page: 2, Limit: 3, Offset: 3, Total: 4}, page)
+
+	houses, page, err = ucodeApi.GetList(ctx, "houses", nil, ucodesdk.WithPage(1, 5))
+	assert.NoError(t, err)
+	assert.Len(t, houses, 5)
+	assert.False(t, page.HasNext)
+	assert.Equal(t, 5, page.Total)
+
+	count, err := ucodeApi.Count(ctx, "houses", map[string]interface{}{"price": 20000})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	updated, err := ucodeApi.UpdateObject(ctx, "houses", map[string]interface{}{"guid": house.GUID(), "price": 16000})
+	assert.NoError(t, err)
+	assert.Equal(t, 16000, updated.Int("price"))
+
+	objects, err := ucodeApi.MultipleUpdate(ctx, "houses", []map[string]interface{}{{"guid": house.GUID(), "name": "renamed"}})
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+
+	rows, err := ucodeApi.GetListAggregation(ctx, "houses", []map[string]interface{}{{"$match": map[string]interface{}{"name": "renamed"}}})
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 1) {
+		assert.Equal(t, 16000, rows[0].Int("price"))
+	}
+
+	assert.NoError(t, ucodeApi.Delete(ctx, "houses", house.GUID()))
+	_, err = ucodeApi.GetSingleSlim(ctx, "houses", house.GUID())
+	assert.ErrorIs(t, err, ucodesdk.ErrNotFound)
+
+	server.FailWhen(func(method, path string, body []byte) bool { return true })
+	_, _, err = ucodeApi.GetListSlim(ctx, "houses", nil)
+	var httpErr *ucodesdk.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+}
+
+func TestListPageInfo(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	ucodeApi := server.Client("test_app_id")
+	for i := 0; i < 25; i++ {
+		server.Seed("test_app_id", "houses", map[string]interface{}{"name": fmt.Sprintf("house_%d", i)})
+	}
+
+	list, _, err := ucodeApi.GetListSlim(&ucodesdk.ArgumentWithPegination{TableSlug: "houses", Request: ucodesdk.Request{Data: map[string]interface{}{}}, Page: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, ucodesdk.PageInfo{Page: 2, Limit: 10, Offset: 10, Total: 25, HasNext: true}, list.PageInfo())
+
+	list, _, err = ucodeApi.GetList(&ucodesdk.ArgumentWithPegination{TableSlug: "houses", Request: ucodesdk.Request{Data: map[string]interface{}{}}, Page: 3})
+	assert.NoError(t, err)
+	total, ok := list.Total()
+	assert.True(t, ok)
+	assert.Equal(t, 25, total)
+	assert.False(t, list.PageInfo().HasNext)
+}
+
+func TestCountWithoutTotal(t *testing.T) {
+	// a server that does not send the count
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		response := []map[string]interface{}{}
+		for i := offset; i < offset+limit && i < 1234; i++ {
+			response = append(response, map[string]interface{}{"guid": strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": map[string]interface{}{"response": response}}})
+	}))
+	defer server.Close()
+
+	ucodeApi := ucodesdk.New(&ucodesdk.Config{AppId: "test_app_id", BaseURL: server.URL})
+
+	count, err := ucodeApi.V2().Count(context.Background(), "houses", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1234, count)
+}
+
+func TestResponseCount(t *testing.T) {
+	var (
+		created ucodesdk.Datas
+		list    ucodesdk.GetListClientApiResponse
+	)
+	created.Data.Data.Data = map[string]interface{}{"guid": "1"}
+	list.Data.Data.Response = []map[string]interface{}{{"guid": "1"}, {"guid": "2"}}
+
+	assert.Equal(t, 1, created.Count())
+	assert.Equal(t, "1", created.Items()[0].GUID())
+	assert.Equal(t, 2, list.Count())
+	assert.Equal(t, "1", list.Item().GUID())
+	assert.Equal(t, 0, ucodesdk.ClientApiUpdateResponse{}.Count())
+	assert.Nil(t, ucodesdk.ClientApiMultipleUpdateResponse{}.Item())
+}
+
+func TestV2Relations(t *testing.T) {
+	server := ucodetest.NewServer()
+	defer server.Close()
+
+	var (
+		appId    = "test_app_id"
+		ucodeApi = server.Client(appId).V2()
+		ctx      = context.Background()
+	)
+
+	buildings := server.Seed(appId, "building", map[string]interface{}{"name": "tower", "floors": 20})
+	rooms := server.Seed(appId, "room", map[string]interface{}{"name": "101", "floor": 1, "building_id": buildings[0]})
+	houses := server.Seed(appId, "houses",
+		map[string]interface{}{"name": "first", "room_id": rooms[0]},
+		map[string]interface{}{"name": "second", "room_id": rooms[0]},
+	)
+
+	house, err := ucodeApi.GetSingleSlim(ctx, "houses", houses[0], ucodesdk.WithRelations("room"))
+	assert.NoError(t, err)
+	assert.Equal(t, "101", house.Object("room").String("name"))
+
+	list, _, err := ucodeApi.GetListSlim(ctx, "houses", nil,
+		ucodesdk.WithRelations("room.building"), ucodesdk.WithRelationFields("room", "name"))
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		room := list[1].Object("room")
+		assert.Equal(t, "101", room.String("name"))
+		assert.False(t, room.Has("floor"))
+		assert.Equal(t, 20, room.Object("building").Int("floors"))
+	}
+
+	type Building struct {
+		Name string `json:"name"`
+	}
+	type Room struct {
+		Name     string    `json:"name"`
+		Building *Building `json:"building_id_data"`
+	}
+	var decoded struct {
+		Name string `json:"name"`
+		Room *Room  `json:"room_id_data"`
+	}
+	assert.NoError(t, list[0].Decode(&decoded))
+	if assert.NotNil(t, decoded.Room) && assert.NotNil(t, decoded.Room.Building) {
+		assert.Equal(t, "tower", decoded.Room.Building.Name)
+	}
+}